@@ -1,6 +1,7 @@
 package arg_parser
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -29,6 +30,8 @@ func parseRange(text string, ids *[]int) error {
 		for i := start; i <= end; i++ {
 			*ids = append(*ids, i)
 		}
+	} else {
+		return fmt.Errorf("Invalid range \"%s\"", text)
 	}
 
 	return nil
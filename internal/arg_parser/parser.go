@@ -6,6 +6,8 @@ const (
 	ConfigStage ParseStage = iota
 	FilterStage
 	ArgStage
+	// Every argument after `--` in the arg stage is text
+	TextStage
 )
 
 type ArgParser struct{}
@@ -37,7 +39,17 @@ func (p *ArgParser) Parse(args []string) ParseContext {
 	text := ""
 	ids := []int{}
 
+	// The flag waiting for its value in the next argument (if any)
+	var flag *FlagArg
+
 	for _, arg := range args {
+		if flag != nil {
+			flag.Value = arg
+			ctx.Args = append(ctx.Args, *flag)
+			flag = nil
+			continue
+		}
+
 		if stage == ConfigStage {
 			// If the argument is a config override (e.g., `bulk=3`), parse
 			// it and add it to the config.
@@ -51,6 +63,29 @@ func (p *ArgParser) Parse(args []string) ParseContext {
 			stage = FilterStage
 		}
 
+		if stage == TextStage {
+			join(&text, arg)
+			continue
+		}
+
+		if stage == ArgStage && arg == "--" {
+			stage = TextStage
+			continue
+		}
+
+		// Flags are parsed the same way in the filter and arg stages, except
+		// that the arg stage only parses the flags the command accepts so
+		// words like `--force` can be used in titles.
+		if name, value, ok := parseFlag(arg); ok && (stage != ArgStage || commandAcceptsFlag(ctx.Command, name)) {
+			if value == "" && flagTakesValue(name) {
+				flag = &FlagArg{Name: name}
+			} else {
+				ctx.Args = append(ctx.Args, FlagArg{Name: name, Value: value})
+			}
+
+			continue
+		}
+
 		if stage == FilterStage {
 			// If we haven't yet identified the command, start by attempting
 			// to parse the text as the command. Once the command has been
@@ -90,6 +125,13 @@ func (p *ArgParser) Parse(args []string) ParseContext {
 				continue
 			}
 
+			// If the argument is a regex (e.g. title~:^Fix or /^Fix/), add it
+			// as a regex filter.
+			if filter, ok := parseRegex(arg); ok {
+				ctx.Filters = append(ctx.Filters, filter)
+				continue
+			}
+
 			// If the argument starts with a scope (e.g. priority:) and it is
 			// a valid scope, add it as a scope filter.
 			if scope, value := parseScope(arg); scope != "" {
@@ -124,6 +166,12 @@ func (p *ArgParser) Parse(args []string) ParseContext {
 		}
 	}
 
+	// A flag missing its value is still added so commands can report the
+	// missing value.
+	if flag != nil {
+		ctx.Args = append(ctx.Args, *flag)
+	}
+
 	// After finishing iterating over the arguments, we need to add the last
 	// id and text arguments to the result.
 	if len(ids) > 0 {
@@ -134,7 +182,7 @@ func (p *ArgParser) Parse(args []string) ParseContext {
 		switch stage {
 		case ConfigStage, FilterStage:
 			ctx.Filters = append(ctx.Filters, TextFilter{Text: text})
-		case ArgStage:
+		case ArgStage, TextStage:
 			ctx.Args = append(ctx.Args, TextArg{Text: text})
 		}
 	}
@@ -140,6 +140,25 @@ func TestMultipleFiltersCommand(t *testing.T) {
 	}
 }
 
+func TestDateScopeFilter(t *testing.T) {
+	args := split("due:2026-10-25 list")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: List,
+		Filters: []Filter{
+			ScopedFilter{Scope: ScopeDue, Value: "2026-10-25"},
+		},
+		Args: []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
 func TestScopeArgs(t *testing.T) {
 	args := split("11 edit priority: priority:H")
 	parser := New()
@@ -228,6 +247,52 @@ func TestConfigOverrides(t *testing.T) {
 	}
 }
 
+func TestArchiveConfig(t *testing.T) {
+	args := split("archive.after=90d archived=yes archived=No stats")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config: []Config{
+			ArchiveConfig{After: "90d"},
+			ArchivedConfig{Include: true},
+			ArchivedConfig{Include: false},
+		},
+		Command: Stats,
+		Filters: []Filter{},
+		Args:    []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if _, ok := ConfigFromStr("archived=maybe"); ok {
+		t.Errorf("Expected archived=maybe to be invalid")
+	}
+}
+
+func TestPrioritiesConfig(t *testing.T) {
+	args := []string{"priorities=P0,P1, P2", "priority.above:p2", "list"}
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config: []Config{
+			PrioritiesConfig{Levels: []string{"P0", "P1", "P2"}},
+		},
+		Command: List,
+		Filters: []Filter{
+			ScopedFilter{Scope: ScopePriorityAbove, Value: "p2"},
+		},
+		Args: []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
 func TestComplexCommand(t *testing.T) {
 	args := split("bulk=8 +work hi priority:foo edit hello -work world priority:L")
 	parser := New()
@@ -274,3 +339,236 @@ func TestOnlyParsesTheCommandOnce(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, result)
 	}
 }
+
+func TestFormatConfig(t *testing.T) {
+	args := split("format=json +work list")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config: []Config{
+			FormatConfig{Format: "json"},
+		},
+		Command: List,
+		Filters: []Filter{
+			TagFilter{Operator: Include, Tag: "work"},
+		},
+		Args: []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestTagPatterns(t *testing.T) {
+	args := split("tag.children=true +work -work.* +*review* list")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config: []Config{
+			TagConfig{Children: true},
+		},
+		Command: List,
+		Filters: []Filter{
+			TagFilter{Operator: Include, Tag: "work"},
+			TagFilter{Operator: Exclude, Tag: "work.*"},
+			TagFilter{Operator: Include, Tag: "*review*"},
+		},
+		Args: []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// The same values as `archived=` are accepted
+	for value, children := range map[string]bool{"yes": true, "No": false, "1": true, "FALSE": false} {
+		config, ok := ConfigFromStr("tag.children=" + value)
+		if !ok || config != (TagConfig{Children: children}) {
+			t.Errorf("Expected tag.children=%s to be %v, got %v", value, children, config)
+		}
+	}
+
+	if _, ok := ConfigFromStr("tag.children=maybe"); ok {
+		t.Errorf("Expected tag.children=maybe to be invalid")
+	}
+}
+
+func TestRegexFilters(t *testing.T) {
+	args := []string{"title~:^Fix.*login$", "/a:b/i", "url~:https?://", "fix", "/etc/hosts", "done"}
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: Done,
+		Filters: []Filter{
+			RegexFilter{Field: "title", Pattern: "^Fix.*login$"},
+			RegexFilter{Field: "title", Pattern: "(?i)a:b"},
+			RegexFilter{Field: "url", Pattern: "https?://"},
+			TextFilter{Text: "fix /etc/hosts"},
+		},
+		Args: []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestColorConfig(t *testing.T) {
+	args := []string{"theme=light", "color.tag.urgent=bold red", "color.priority.H=bold", "list"}
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config: []Config{
+			ThemeConfig{Theme: "light"},
+			ColorConfig{Key: "tag.urgent", Value: "bold red"},
+			ColorConfig{Key: "priority.H", Value: "bold"},
+		},
+		Command: List,
+		Filters: []Filter{},
+		Args:    []Arg{},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFlagArgs(t *testing.T) {
+	args := split("serve --addr 127.0.0.1:8080 --token=secret --verbose")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: Serve,
+		Filters: []Filter{},
+		Args: []Arg{
+			FlagArg{Name: "addr", Value: "127.0.0.1:8080"},
+			FlagArg{Name: "token", Value: "secret"},
+			FlagArg{Name: "verbose", Value: ""},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestShortFlags(t *testing.T) {
+	args := split("12 -work delete -y")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: Delete,
+		Filters: []Filter{
+			TagFilter{Operator: Exclude, Tag: "work"},
+			IdFilter{Ids: []int{12}},
+		},
+		Args: []Arg{
+			FlagArg{Name: "yes", Value: ""},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestFlagsInArgs(t *testing.T) {
+	args := split("add Document the --force option -y +docs")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: Add,
+		Filters: []Filter{},
+		Args: []Arg{
+			TagArg{Operator: Exclude, Tag: "y"},
+			TagArg{Operator: Include, Tag: "docs"},
+			TextArg{Text: "Document the --force option"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	args = split("search login --all --format=json")
+	result = parser.Parse(args)
+
+	expected = ParseContext{
+		Config:  []Config{},
+		Command: Search,
+		Filters: []Filter{},
+		Args: []Arg{
+			FlagArg{Name: "all", Value: ""},
+			TextArg{Text: "login --format=json"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	args = split("+release duplicate due:fri -y")
+	result = parser.Parse(args)
+
+	expected = ParseContext{
+		Config:  []Config{},
+		Command: Duplicate,
+		Filters: []Filter{TagFilter{Operator: Include, Tag: "release"}},
+		Args: []Arg{
+			ScopedArg{Scope: ScopeDue, Value: "fri"},
+			FlagArg{Name: "yes", Value: ""},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	args = split("tag rename errands shopping -y")
+	result = parser.Parse(args)
+
+	expected = ParseContext{
+		Config:  []Config{},
+		Command: Tag,
+		Filters: []Filter{},
+		Args: []Arg{
+			FlagArg{Name: "yes", Value: ""},
+			TextArg{Text: "rename errands shopping"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+func TestEndOfArgs(t *testing.T) {
+	args := split("add Use -- -y +here priority:H --all")
+	parser := New()
+	result := parser.Parse(args)
+
+	expected := ParseContext{
+		Config:  []Config{},
+		Command: Add,
+		Filters: []Filter{},
+		Args: []Arg{
+			TextArg{Text: "Use -y +here priority:H --all"},
+		},
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
@@ -0,0 +1,32 @@
+package arg_parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Fields of regex filters are the name of a task field or attribute
+var fieldPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Parses a regex filter, either matching a field (`title~:^Fix`) or the title
+// using the `/pattern/` form. Adding an `i` after the closing slash makes the
+// pattern case-insensitive (`/fix/i`).
+func parseRegex(arg string) (RegexFilter, bool) {
+	if field, pattern, ok := strings.Cut(arg, "~:"); ok && fieldPattern.MatchString(field) && pattern != "" {
+		return RegexFilter{Field: field, Pattern: pattern}, true
+	}
+
+	if len(arg) < 3 || arg[0] != '/' {
+		return RegexFilter{}, false
+	}
+
+	if pattern, ok := strings.CutSuffix(arg[1:], "/"); ok && pattern != "" {
+		return RegexFilter{Field: "title", Pattern: pattern}, true
+	}
+
+	if pattern, ok := strings.CutSuffix(arg[1:], "/i"); ok && pattern != "" {
+		return RegexFilter{Field: "title", Pattern: "(?i)" + pattern}, true
+	}
+
+	return RegexFilter{}, false
+}
@@ -1,14 +1,16 @@
 package arg_parser
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 func scopeFromStr(str string) (Scope, bool) {
-	switch Scope(str) {
-	case ScopePriority:
+	if slices.Contains(Scopes(), Scope(str)) {
 		return Scope(str), true
-	default:
-		return "", false
 	}
+
+	return "", false
 }
 
 func parseScope(arg string) (Scope, string) {
@@ -6,7 +6,9 @@ import (
 
 func parseTag(text string) (Operator, string) {
 
-	if len(text) < 2 || text[1] == ' ' {
+	// Words starting with `--` are flags, or text if the command doesn't
+	// accept the flag.
+	if len(text) < 2 || text[1] == ' ' || strings.HasPrefix(text, "--") {
 		return "", ""
 	}
 
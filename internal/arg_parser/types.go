@@ -1,6 +1,7 @@
 package arg_parser
 
 import (
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -15,25 +16,73 @@ const (
 type Scope string
 
 const (
-	ScopePriority Scope = "priority"
+	ScopePriority  Scope = "priority"
+	ScopeProject   Scope = "project"
+	ScopeDue       Scope = "due"
+	ScopeScheduled Scope = "scheduled"
+	ScopeWait      Scope = "wait"
+	ScopeParent    Scope = "parent"
+	ScopeFrom      Scope = "from"
+
+	ScopePriorityAbove Scope = "priority.above"
+	ScopePriorityBelow Scope = "priority.below"
 )
 
+// Returns all scopes that can be used in filters and args.
+func Scopes() []Scope {
+	return []Scope{
+		ScopePriority, ScopeProject, ScopeDue, ScopeScheduled, ScopeWait, ScopeParent, ScopeFrom,
+		ScopePriorityAbove, ScopePriorityBelow,
+	}
+}
+
 type Command string
 
 const (
-	List    Command = "list"
-	Add     Command = "add"
-	Done    Command = "done"
-	Edit    Command = "edit"
-	Show    Command = "show"
-	Start   Command = "start"
-	Stop    Command = "stop"
-	Get     Command = "get"
-	Delete  Command = "delete"
-	Help    Command = "help"
-	Version Command = "version"
+	List      Command = "list"
+	Add       Command = "add"
+	Done      Command = "done"
+	Edit      Command = "edit"
+	Show      Command = "show"
+	Start     Command = "start"
+	Stop      Command = "stop"
+	Get       Command = "get"
+	Subtask   Command = "subtask"
+	Check     Command = "check"
+	Uncheck   Command = "uncheck"
+	Delete    Command = "delete"
+	Duplicate Command = "duplicate"
+	Help      Command = "help"
+	Version   Command = "version"
+	UI        Command = "ui"
+	Sync      Command = "sync"
+	Serve     Command = "serve"
+	Export    Command = "export"
+	Import    Command = "import"
+	Stats     Command = "stats"
+	Burndown  Command = "burndown"
+	History   Command = "history"
+	Calendar  Command = "calendar"
+	Waiting   Command = "waiting"
+	Tags      Command = "tags"
+	Tag       Command = "tag"
+	Blueprint Command = "blueprint"
+	Search    Command = "search"
+	Trash     Command = "trash"
+	Restore   Command = "restore"
+	Purge     Command = "purge"
+	Archive   Command = "archive"
+
+	Completion Command = "completion"
+	// Hidden command used by shell completion scripts
+	Complete Command = "_complete"
 )
 
+// Returns all documented commands, excluding aliases and hidden commands.
+func Commands() []Command {
+	return []Command{List, Add, Done, Edit, Show, Start, Stop, Get, Duplicate, Subtask, Check, Uncheck, Delete, Trash, Restore, Purge, Archive, Search, UI, Tags, Tag, Blueprint, Sync, Serve, Export, Import, Waiting, Stats, Burndown, History, Calendar, Completion, Help, Version}
+}
+
 type Filter interface{}
 
 type IdFilter struct {
@@ -54,6 +103,12 @@ type TextFilter struct {
 	Text string
 }
 
+// A filter matching a field of the task against a regular expression
+type RegexFilter struct {
+	Field   string
+	Pattern string
+}
+
 type Arg interface{}
 
 type TagArg struct {
@@ -70,6 +125,13 @@ type TextArg struct {
 	Text string
 }
 
+// A command line flag such as `--addr 127.0.0.1:7777` or `--addr=127.0.0.1:7777`.
+// Flags can be used at any position after the config overrides.
+type FlagArg struct {
+	Name  string
+	Value string
+}
+
 type Config interface{}
 
 type BulkConfig struct {
@@ -80,10 +142,67 @@ type ContextConfig struct {
 	Context string
 }
 
+type FormatConfig struct {
+	Format string
+}
+
+type OverflowConfig struct {
+	Overflow string
+}
+
+type ThemeConfig struct {
+	Theme string
+}
+
+type SyncConfig struct {
+	Dir string
+}
+
+// Whether tag filters also match child tags, such as `+work` matching the tag
+// `work.infra`
+type TagConfig struct {
+	Children bool
+}
+
+// The date field used to place tasks in the calendar
+type CalendarConfig struct {
+	Date string
+}
+
+// Whether to complete a task when the last item of its checklist is checked,
+// one of `ask`, `yes`, or `no`
+type ChecklistConfig struct {
+	Complete string
+}
+
+// The age after which completed tasks are archived on launch, such as `90d`
+type ArchiveConfig struct {
+	After string
+}
+
+// Whether reports include archived tasks
+type ArchivedConfig struct {
+	Include bool
+}
+
+// The priorities from highest to lowest, such as `H,M,L`
+type PrioritiesConfig struct {
+	Levels []string
+}
+
+type ColorConfig struct {
+	Key   string
+	Value string
+}
+
 func commandFromStr(str string) (Command, bool) {
-	switch Command(str) {
-	case List, Add, Done, Edit, Show, Start, Stop, Get, Delete, Help, Version:
+	if slices.Contains(Commands(), Command(str)) {
 		return Command(str), true
+	}
+
+	switch Command(str) {
+	case Complete:
+		return Complete, true
 	case "ls":
 		return List, true
 	default:
@@ -93,13 +212,75 @@ func commandFromStr(str string) (Command, bool) {
 
 func commandAcceptsArgs(command Command) bool {
 	switch command {
-	case Add, Edit, Get:
+	case Add, Edit, Get, Duplicate, Subtask, Check, Uncheck, Search, Tag, Blueprint, Purge, Archive, Sync, Import, Burndown, History, Calendar, Completion, Complete:
 		return true
 	default:
 		return false
 	}
 }
 
+// Returns true if the command accepts the flag after its args. Other words
+// that look like flags are treated as text.
+func commandAcceptsFlag(command Command, name string) bool {
+	switch command {
+	case Check, Uncheck, Purge, Duplicate, Tag:
+		return name == "yes"
+	case Search:
+		return name == "all"
+	case Import:
+		return name == "format"
+	default:
+		return false
+	}
+}
+
+// Returns true if the flag requires a value, in which case the value can be
+// passed as the next argument rather than with an `=`.
+func flagTakesValue(name string) bool {
+	switch name {
+	case "addr", "format", "token", "weeks":
+		return true
+	default:
+		return false
+	}
+}
+
+// Short flags and the long flags they are aliases for. Short flags take
+// precedence over tags, so `-y` can't be used to exclude the tag `y`.
+var shortFlags = map[string]string{
+	"-y": "yes",
+}
+
+// Parses a long flag (e.g., `--addr=127.0.0.1:7777`) or short flag (e.g.,
+// `-y`) returning the flag name and its value, if included in the argument.
+func parseFlag(arg string) (string, string, bool) {
+	if name, ok := shortFlags[arg]; ok {
+		return name, "", true
+	}
+
+	name, ok := strings.CutPrefix(arg, "--")
+	if !ok || name == "" {
+		return "", "", false
+	}
+
+	name, value, _ := strings.Cut(name, "=")
+	return name, value, true
+}
+
+// Parses a boolean config value, which can be `yes` or `no` as well as any
+// value accepted by `strconv.ParseBool`.
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "yes":
+		return true, true
+	case "no":
+		return false, true
+	}
+
+	b, err := strconv.ParseBool(strings.ToLower(value))
+	return b, err == nil
+}
+
 func ConfigFromStr(str string) (Config, bool) {
 	parts := strings.Split(str, "=")
 
@@ -118,7 +299,55 @@ func ConfigFromStr(str string) (Config, bool) {
 			return nil, false
 		}
 
+	case "format":
+		return FormatConfig{Format: parts[1]}, true
+
+	case "overflow":
+		return OverflowConfig{Overflow: parts[1]}, true
+
+	case "theme":
+		return ThemeConfig{Theme: parts[1]}, true
+
+	case "sync":
+		return SyncConfig{Dir: parts[1]}, true
+
+	case "tag.children":
+		if children, ok := parseBool(parts[1]); ok {
+			return TagConfig{Children: children}, true
+		} else {
+			return nil, false
+		}
+
+	case "calendar.date":
+		return CalendarConfig{Date: parts[1]}, true
+
+	case "checklist.complete":
+		return ChecklistConfig{Complete: parts[1]}, true
+
+	case "archive.after":
+		return ArchiveConfig{After: parts[1]}, true
+
+	case "archived":
+		if include, ok := parseBool(parts[1]); ok {
+			return ArchivedConfig{Include: include}, true
+		} else {
+			return nil, false
+		}
+
+	case "priorities":
+		levels := strings.Split(parts[1], ",")
+		for i, level := range levels {
+			levels[i] = strings.TrimSpace(level)
+		}
+
+		return PrioritiesConfig{Levels: levels}, true
+
 	default:
+		// Color rules (e.g., `color.tag.urgent=red`)
+		if key, ok := strings.CutPrefix(parts[0], "color."); ok && key != "" {
+			return ColorConfig{Key: key, Value: parts[1]}, true
+		}
+
 		return nil, false
 	}
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+)
+
+// Returns the directory containing the tsk configuration, following the XDG
+// base directory specification.
+func Dir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "tsk"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("Failed to find config directory: %w", err)
+	}
+
+	return filepath.Join(home, ".config", "tsk"), nil
+}
+
+// Loads the config overrides from the config file. Each line of the file is a
+// config override using the same syntax as the command line (e.g.,
+// `overflow=truncate`). Blank lines and lines starting with `#` are ignored.
+func Load() ([]arg_parser.Config, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Join(dir, "config"))
+	if os.IsNotExist(err) {
+		return []arg_parser.Config{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to read config: %w", err)
+	}
+
+	defer file.Close()
+	return parse(file)
+}
+
+func parse(r io.Reader) ([]arg_parser.Config, error) {
+	configs := []arg_parser.Config{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		config, ok := arg_parser.ConfigFromStr(text)
+		if !ok {
+			return nil, fmt.Errorf("Invalid config on line %d: %s", line, text)
+		}
+
+		configs = append(configs, config)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read config: %w", err)
+	}
+
+	return configs, nil
+}
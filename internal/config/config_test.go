@@ -0,0 +1,29 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	configs, err := parse(strings.NewReader(`
+# Defaults for every command
+bulk=2
+
+overflow=truncate
+`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, configs, []arg_parser.Config{
+		arg_parser.BulkConfig{Size: 2},
+		arg_parser.OverflowConfig{Overflow: "truncate"},
+	})
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := parse(strings.NewReader("bulk=2\nfoo"))
+	assert.EqualError(t, err, "Invalid config on line 2: foo")
+}
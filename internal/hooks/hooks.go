@@ -0,0 +1,157 @@
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/config"
+)
+
+type Event string
+
+const (
+	// Runs before a task is added with the new task on stdin
+	OnAdd Event = "on-add"
+	// Runs before a task is modified with the original and modified task on
+	// stdin
+	OnModify Event = "on-modify"
+	// Runs before a task is completed with the original and completed task on
+	// stdin
+	OnDone Event = "on-done"
+	// Runs before a task is deleted with the task on stdin
+	OnDelete Event = "on-delete"
+	// Runs before any command is executed
+	OnLaunch Event = "on-launch"
+	// Runs after a command is executed
+	OnExit Event = "on-exit"
+)
+
+// Returns the hook scripts for an event in the order they should run. Hooks
+// are executable files in the hooks directory named after the event, with an
+// optional suffix to allow multiple hooks per event (e.g., `on-add.tags`).
+func find(event Event) ([]string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "hooks"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("Failed to read hooks: %w", err)
+	}
+
+	var scripts []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != string(event) && !strings.HasPrefix(name, string(event)+".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		// Non-executable files are ignored so hooks can be disabled by
+		// removing the executable bit.
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+
+		scripts = append(scripts, filepath.Join(dir, "hooks", name))
+	}
+
+	sort.Strings(scripts)
+	return scripts, nil
+}
+
+// Splits hook output into the task JSON (if any) and feedback messages. The
+// task JSON must be on the first line of the output.
+func parseOutput(output []byte) ([]byte, []string) {
+	var task []byte
+	var messages []string
+
+	for i, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if i == 0 && strings.HasPrefix(strings.TrimSpace(line), "{") {
+			task = []byte(line)
+		} else if line != "" {
+			messages = append(messages, line)
+		}
+	}
+
+	return task, messages
+}
+
+// Runs the hooks for an event. Each line of input is written to the stdin of
+// the hook. For events that modify tasks, the last line of input is the task
+// JSON which hooks can modify by printing new task JSON on the first line of
+// their output. The modified task is passed to the next hook and returned.
+//
+// Any other output from the hook is printed as feedback. If a hook exits with
+// a non-zero status, the change is rejected and an error containing the hook's
+// feedback is returned.
+func Run(event Event, input ...[]byte) ([]byte, error) {
+	scripts, err := find(event)
+	if err != nil {
+		return nil, err
+	}
+
+	var task []byte
+	if len(input) > 0 {
+		task = input[len(input)-1]
+	}
+
+	for _, script := range scripts {
+		// The task may have been modified by a previous hook
+		var stdin bytes.Buffer
+		for i, line := range input {
+			if i == len(input)-1 {
+				line = task
+			}
+
+			stdin.Write(line)
+			stdin.WriteByte('\n')
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command(script)
+		cmd.Stdin = &stdin
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		runErr := cmd.Run()
+		modified, messages := parseOutput(stdout.Bytes())
+		name := filepath.Base(script)
+
+		if runErr != nil {
+			message := strings.TrimSpace(strings.Join(messages, "\n") + "\n" + stderr.String())
+			if message == "" {
+				message = runErr.Error()
+			}
+
+			return nil, fmt.Errorf("Hook %s rejected the change: %s", name, message)
+		}
+
+		for _, message := range messages {
+			fmt.Fprintln(os.Stderr, message)
+		}
+
+		if stderr.Len() > 0 {
+			fmt.Fprint(os.Stderr, stderr.String())
+		}
+
+		if modified != nil && len(input) > 0 {
+			task = modified
+		}
+	}
+
+	return task, nil
+}
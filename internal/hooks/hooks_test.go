@@ -0,0 +1,62 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeHook(t *testing.T, name string, script string) {
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "tsk", "hooks")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+}
+
+func setup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts require a POSIX shell")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+func TestRunWithoutHooks(t *testing.T) {
+	setup(t)
+
+	output, err := Run(OnAdd, []byte(`{"title":"a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, string(output), `{"title":"a"}`)
+}
+
+func TestRunModifiesTask(t *testing.T) {
+	setup(t)
+	writeHook(t, "on-modify", "#!/bin/sh\nread before\nread after\necho '{\"title\":\"b\"}'\n")
+	writeHook(t, "on-modify.second", "#!/bin/sh\nread before\nread after\necho \"$after\" | sed 's/b/c/'\n")
+
+	// Hooks for other events are ignored
+	writeHook(t, "on-add", "#!/bin/sh\nexit 1\n")
+
+	output, err := Run(OnModify, []byte(`{"title":"a"}`), []byte(`{"title":"a"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, string(output), `{"title":"c"}`)
+}
+
+func TestRunRejectsChange(t *testing.T) {
+	setup(t)
+	writeHook(t, "on-delete", "#!/bin/sh\necho 'Cannot delete this task'\nexit 1\n")
+
+	_, err := Run(OnDelete, []byte(`{"title":"a"}`))
+	assert.EqualError(t, err, "Hook on-delete rejected the change: Cannot delete this task")
+}
+
+func TestIgnoresNonExecutableHooks(t *testing.T) {
+	setup(t)
+	writeHook(t, "on-launch", "#!/bin/sh\nexit 1\n")
+	assert.NoError(t, os.Chmod(filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "tsk", "hooks", "on-launch"), 0644))
+
+	_, err := Run(OnLaunch)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,431 @@
+// Package ical reads and writes tasks as iCalendar (RFC 5545) VTODO
+// components.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+const (
+	// The maximum length of a content line in octets, excluding the line break
+	lineLength = 75
+	dateTime   = "20060102T150405Z"
+)
+
+// Maps task statuses to VTODO statuses
+var statuses = map[storage.TaskStatus]string{
+	storage.TaskStatusPending: "NEEDS-ACTION",
+	storage.TaskStatusActive:  "IN-PROCESS",
+	storage.TaskStatusDone:    "COMPLETED",
+}
+
+// Returns the VTODO priority of the configured priority with the rank. The
+// priorities are spread from 1 (the highest) to 9 (the lowest), so `H`, `M`,
+// and `L` are written as 1, 5, and 9.
+func vtodoPriority(rank int, count int) int {
+	if count == 1 {
+		return 1
+	}
+
+	return 1 + int(math.Round(float64(rank)*8/float64(count-1)))
+}
+
+// Task fields without a matching VTODO property are written to non-standard
+// properties so they are not lost when importing. Priorities are only written
+// to `X-TSK-PRIORITY` if they aren't one of the configured priorities.
+const (
+	priorityProperty  = "X-TSK-PRIORITY"
+	projectProperty   = "X-TSK-PROJECT"
+	attributeProperty = "X-TSK-ATTRIBUTE"
+	waitProperty      = "X-TSK-WAIT"
+)
+
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+func unescape(text string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' && i+1 < len(text) {
+			i++
+
+			switch text[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(text[i])
+			}
+
+			continue
+		}
+
+		b.WriteByte(text[i])
+	}
+
+	return b.String()
+}
+
+// Splits a list of values on unescaped commas.
+func splitList(value string) []string {
+	var values []string
+	start := 0
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' {
+			i++
+		} else if value[i] == ',' {
+			values = append(values, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(values, value[start:])
+}
+
+// Writes a content line, folding it into multiple lines if it is longer than
+// the maximum line length. Lines are only folded between UTF-8 characters.
+func writeLine(w *bufio.Writer, line string) {
+	limit := lineLength
+
+	for len(line) > limit {
+		i := limit
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+
+		w.WriteString(line[:i])
+		w.WriteString("\r\n ")
+		line = line[i:]
+
+		// Continuation lines start with a space which counts toward the limit
+		limit = lineLength - 1
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func formatDate(t time.Time) string {
+	return t.Local().Format("20060102")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTime)
+}
+
+// Writes the tasks as an iCalendar object with a VTODO for each task.
+func Write(w io.Writer, tasks []storage.Task) error {
+	b := bufio.NewWriter(w)
+
+	writeLine(b, "BEGIN:VCALENDAR")
+	writeLine(b, "VERSION:2.0")
+	writeLine(b, "PRODID:-//tsk//tsk//EN")
+
+	for _, task := range tasks {
+		writeLine(b, "BEGIN:VTODO")
+		writeLine(b, "UID:"+escape(task.Id))
+		writeLine(b, "DTSTAMP:"+formatTime(task.UpdatedAt))
+		writeLine(b, "SUMMARY:"+escape(task.Title))
+
+		if status, ok := statuses[task.Status]; ok {
+			writeLine(b, "STATUS:"+status)
+		}
+
+		if rank := storage.PriorityRank(task.Priority); rank >= 0 {
+			writeLine(b, "PRIORITY:"+strconv.Itoa(vtodoPriority(rank, len(storage.Priorities()))))
+		} else if task.Priority != "" {
+			writeLine(b, priorityProperty+":"+escape(task.Priority))
+		}
+
+		if len(task.Tags) > 0 {
+			tags := make([]string, len(task.Tags))
+			for i, tag := range task.Tags {
+				tags[i] = escape(tag)
+			}
+
+			writeLine(b, "CATEGORIES:"+strings.Join(tags, ","))
+		}
+
+		if task.Project != "" {
+			writeLine(b, projectProperty+":"+escape(task.Project))
+		}
+
+		keys := make([]string, 0, len(task.Attributes))
+		for key := range task.Attributes {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			writeLine(b, attributeProperty+":"+escape(key+"="+task.Attributes[key]))
+		}
+
+		if task.Scheduled != nil {
+			writeLine(b, "DTSTART;VALUE=DATE:"+formatDate(*task.Scheduled))
+		}
+
+		if task.Due != nil {
+			writeLine(b, "DUE;VALUE=DATE:"+formatDate(*task.Due))
+		}
+
+		if task.Wait != nil {
+			writeLine(b, waitProperty+";VALUE=DATE:"+formatDate(*task.Wait))
+		}
+
+		writeLine(b, "CREATED:"+formatTime(task.CreatedAt))
+		writeLine(b, "LAST-MODIFIED:"+formatTime(task.UpdatedAt))
+
+		if task.CompletedAt != nil {
+			writeLine(b, "COMPLETED:"+formatTime(*task.CompletedAt))
+		}
+
+		writeLine(b, "END:VTODO")
+	}
+
+	writeLine(b, "END:VCALENDAR")
+	return b.Flush()
+}
+
+type property struct {
+	name  string
+	value string
+}
+
+// Reads the content lines of an iCalendar object, unfolding lines that were
+// folded when written.
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// Parses a content line into its name and value. Parameters are ignored, but
+// the value separator may not appear inside a quoted parameter value.
+func parseLine(line string) (property, bool) {
+	quoted := false
+
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == ':' && !quoted:
+			name, _, _ := strings.Cut(line[:i], ";")
+			return property{name: strings.ToUpper(name), value: line[i+1:]}, true
+		}
+	}
+
+	return property{}, false
+}
+
+// Parses a DATE or DATE-TIME value. Times without a UTC offset are treated as
+// local time.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(dateTime, value); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.ParseInLocation("20060102T150405", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("20060102", value, time.Local)
+}
+
+// Converts a VTODO priority to a configured priority. Values between two
+// priorities are rounded away from 5 (medium), so with the default priorities
+// 1 to 4 are `H`, 5 is `M`, and 6 to 9 are `L`.
+func parsePriority(value string) (string, error) {
+	priority, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("Invalid priority \"%s\"", value)
+	}
+
+	if priority <= 0 {
+		return "", nil
+	}
+
+	levels := storage.Priorities()
+
+	if priority <= 5 {
+		for i := len(levels) - 1; i >= 0; i-- {
+			if vtodoPriority(i, len(levels)) <= priority {
+				return levels[i], nil
+			}
+		}
+	}
+
+	for i := range levels {
+		if vtodoPriority(i, len(levels)) >= priority {
+			return levels[i], nil
+		}
+	}
+
+	return levels[len(levels)-1], nil
+}
+
+func parseTodo(props []property) (storage.Task, error) {
+	task := storage.Task{
+		Status: storage.TaskStatusPending,
+		Tags:   make([]string, 0),
+	}
+
+	custom := ""
+	for _, prop := range props {
+		var err error
+
+		switch prop.name {
+		case "UID":
+			task.Id = unescape(prop.value)
+		case "SUMMARY":
+			task.Title = unescape(prop.value)
+		case "STATUS":
+			for status, value := range statuses {
+				if strings.EqualFold(prop.value, value) {
+					task.Status = status
+				}
+			}
+		case "PRIORITY":
+			task.Priority, err = parsePriority(prop.value)
+		case priorityProperty:
+			custom = unescape(prop.value)
+		case projectProperty:
+			task.Project = unescape(prop.value)
+		case attributeProperty:
+			if key, value, ok := strings.Cut(unescape(prop.value), "="); ok {
+				if task.Attributes == nil {
+					task.Attributes = make(map[string]string)
+				}
+
+				task.Attributes[key] = value
+			}
+		case "CATEGORIES":
+			for _, tag := range splitList(prop.value) {
+				if tag = strings.TrimSpace(unescape(tag)); tag != "" {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+		case "CREATED":
+			task.CreatedAt, err = parseTime(prop.value)
+		case "LAST-MODIFIED":
+			task.UpdatedAt, err = parseTime(prop.value)
+		case "COMPLETED":
+			var completed time.Time
+			completed, err = parseTime(prop.value)
+			task.CompletedAt = &completed
+		case "DUE":
+			var due time.Time
+			due, err = parseTime(prop.value)
+			task.Due = &due
+		case "DTSTART":
+			var scheduled time.Time
+			scheduled, err = parseTime(prop.value)
+			task.Scheduled = &scheduled
+		case waitProperty:
+			var wait time.Time
+			wait, err = parseTime(prop.value)
+			task.Wait = &wait
+		}
+
+		if err != nil {
+			return task, fmt.Errorf("Invalid %s: %w", prop.name, err)
+		}
+	}
+
+	if custom != "" {
+		task.Priority = custom
+	}
+
+	if task.Id == "" {
+		return task, errors.New("Missing UID")
+	}
+
+	return task, nil
+}
+
+// Reads the VTODO components of an iCalendar object as tasks. Other
+// components, such as events, are ignored.
+func Read(r io.Reader) ([]storage.Task, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read calendar: %w", err)
+	}
+
+	tasks := []storage.Task{}
+	var props []property
+
+	// The components the current line is nested in
+	var components []string
+
+	for i, line := range lines {
+		prop, ok := parseLine(line)
+		if !ok {
+			return nil, fmt.Errorf("Invalid calendar line %d: %s", i+1, line)
+		}
+
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			components = append(components, component)
+
+			if component == "VTODO" {
+				props = nil
+			}
+
+			continue
+
+		case "END":
+			if len(components) == 0 {
+				return nil, fmt.Errorf("Invalid calendar line %d: unexpected END", i+1)
+			}
+
+			if components[len(components)-1] == "VTODO" {
+				task, err := parseTodo(props)
+				if err != nil {
+					return nil, fmt.Errorf("Invalid VTODO ending on line %d: %w", i+1, err)
+				}
+
+				tasks = append(tasks, task)
+			}
+
+			components = components[:len(components)-1]
+			continue
+		}
+
+		// Properties of nested components, such as alarms, are ignored.
+		if len(components) > 0 && components[len(components)-1] == "VTODO" {
+			props = append(props, prop)
+		}
+	}
+
+	return tasks, nil
+}
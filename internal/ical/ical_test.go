@@ -0,0 +1,128 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	due := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+	scheduled := time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)
+	tasks := []storage.Task{
+		{
+			Id:       "abc",
+			Title:    "Buy milk, eggs; bread",
+			Priority: "H",
+			Status:   storage.TaskStatusActive,
+			Tags:     []string{"home", "a,b"},
+			Project:  "errands",
+			Attributes: map[string]string{
+				"note": "a=b; c",
+			},
+			Due:       &due,
+			Scheduled: &scheduled,
+			Wait:      &scheduled,
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+		},
+		{
+			Id:        "def",
+			Title:     strings.Repeat("long title ", 20),
+			Priority:  "X",
+			Status:    storage.TaskStatusDone,
+			Tags:      []string{},
+			CreatedAt: created,
+			UpdatedAt: created,
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tasks))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		assert.LessOrEqual(t, len(line), lineLength)
+	}
+
+	result, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+func TestRead(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:event",
+		"SUMMARY:Ignored",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"UID:todo",
+		"SUMMARY;LANGUAGE=en:Call ",
+		" mom",
+		"PRIORITY:3",
+		"STATUS:completed",
+		"BEGIN:VALARM",
+		"SUMMARY:Ignored",
+		"END:VALARM",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\n")
+
+	result, err := Read(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.Task{{
+		Id:       "todo",
+		Title:    "Call mom",
+		Priority: "H",
+		Status:   storage.TaskStatusDone,
+		Tags:     []string{},
+	}}, result)
+}
+
+func TestReadMissingUID(t *testing.T) {
+	_, err := Read(strings.NewReader("BEGIN:VTODO\nSUMMARY:Foo\nEND:VTODO\n"))
+	assert.ErrorContains(t, err, "Missing UID")
+}
+
+func TestParsePriority(t *testing.T) {
+	for value, expected := range map[string]string{"0": "", "1": "H", "4": "H", "5": "M", "6": "L", "9": "L"} {
+		priority, err := parsePriority(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, priority, value)
+	}
+}
+
+func TestCustomPriorities(t *testing.T) {
+	defer storage.SetPriorities([]string{"H", "M", "L"})
+	assert.NoError(t, storage.SetPriorities([]string{"P0", "P1", "P2", "P3"}))
+
+	var tasks []storage.Task
+	for _, priority := range []string{"P0", "P1", "P2", "P3"} {
+		tasks = append(tasks, storage.Task{Id: priority, Title: "Task", Priority: priority, Status: storage.TaskStatusPending, Tags: []string{}})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tasks))
+
+	for _, value := range []string{"PRIORITY:1\r\n", "PRIORITY:4\r\n", "PRIORITY:6\r\n", "PRIORITY:9\r\n"} {
+		assert.Contains(t, buf.String(), value)
+	}
+
+	assert.NotContains(t, buf.String(), priorityProperty)
+
+	result, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+
+	for value, expected := range map[string]string{"2": "P0", "3": "P0", "5": "P1", "7": "P3", "8": "P3"} {
+		priority, err := parsePriority(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, priority, value)
+	}
+}
@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// The width of the calendar using the standard layout. Narrower terminals use
+// the compact layout.
+const calendarWidth = 28
+
+// A month grid with the days that have tasks highlighted. Weeks start on
+// Monday.
+type Calendar struct {
+	// Any time in the month to show
+	Month time.Time
+	Today time.Time
+	// The number of tasks on each day of the month
+	Tasks map[int]int
+}
+
+// Formats a day of the month. Days with tasks are marked with a `*` when
+// colors are disabled so they can still be told apart.
+func (c Calendar) day(day int, digits int) string {
+	text := fmt.Sprintf("%*d", digits, day)
+	marker := " "
+
+	var style Style
+	if c.Tasks[day] > 0 {
+		if color.NoColor || len(theme.CalendarTask) == 0 {
+			marker = "*"
+		} else {
+			style = append(style, theme.CalendarTask...)
+		}
+	}
+
+	if c.isToday(day) {
+		style = append(style, theme.CalendarToday...)
+	}
+
+	return style.Sprint(text) + marker
+}
+
+func (c Calendar) isToday(day int) bool {
+	y, m, d := c.Today.Date()
+	return y == c.Month.Year() && m == c.Month.Month() && d == day
+}
+
+// Returns the lines of the calendar using cells of the given width, including
+// the space or marker after each day.
+func (c Calendar) lines(cell int) []string {
+	first := time.Date(c.Month.Year(), c.Month.Month(), 1, 0, 0, 0, 0, time.Local)
+	days := first.AddDate(0, 1, -1).Day()
+	digits := cell - 1
+
+	title := first.Format("January 2006")
+	padding := max((cell*7-1-len(title))/2, 0)
+	lines := []string{strings.Repeat(" ", padding) + title}
+
+	var header []string
+	for i := 0; i < 7; i++ {
+		name := time.Weekday((i + 1) % 7).String()[:digits]
+		header = append(header, fmt.Sprintf("%-*s", digits, name))
+	}
+
+	lines = append(lines, strings.Join(header, " "))
+
+	// The number of empty cells before the first day
+	offset := (int(first.Weekday()) + 6) % 7
+	line := strings.Repeat(" ", offset*cell)
+
+	for day := 1; day <= days; day++ {
+		line += c.day(day, digits)
+
+		if (offset+day)%7 == 0 || day == days {
+			lines = append(lines, strings.TrimRight(line, " "))
+			line = ""
+		}
+	}
+
+	return lines
+}
+
+func (c Calendar) Print() {
+	cell := 4
+	if width, ok := TerminalWidth(); ok && width < calendarWidth {
+		cell = 3
+	}
+
+	for _, line := range c.lines(cell) {
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,41 @@
+package printer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarLines(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	calendar := Calendar{
+		Month: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local),
+		Today: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.Local),
+		Tasks: map[int]int{1: 2, 31: 1},
+	}
+
+	assert.Equal(t, []string{
+		"       October 2026",
+		"Mon Tue Wed Thu Fri Sat Sun",
+		"              1*  2   3   4",
+		"  5   6   7   8   9  10  11",
+		" 12  13  14  15  16  17  18",
+		" 19  20  21  22  23  24  25",
+		" 26  27  28  29  30  31*",
+	}, calendar.lines(4))
+
+	assert.Equal(t, []string{
+		"    October 2026",
+		"Mo Tu We Th Fr Sa Su",
+		"          1* 2  3  4",
+		" 5  6  7  8  9 10 11",
+		"12 13 14 15 16 17 18",
+		"19 20 21 22 23 24 25",
+		"26 27 28 29 30 31*",
+	}, calendar.lines(3))
+}
@@ -0,0 +1,155 @@
+package printer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// The width of charts when stdout is not a terminal
+const defaultChartWidth = 80
+
+// A series of values in a chart. The symbol is used to draw the bars when
+// colors are disabled so the series can be told apart.
+type Series struct {
+	Name   string
+	Style  Style
+	Symbol string
+}
+
+type Bar struct {
+	Label string
+	// The value of each series for the bar
+	Values []int
+}
+
+// A horizontal bar chart. Stacked charts draw the values of each bar on a
+// single line, otherwise each series is drawn on its own line.
+type Chart struct {
+	Series  []Series
+	Bars    []Bar
+	Stacked bool
+}
+
+// Returns the number of cells used to draw a value, scaled so the largest
+// value fills the width. Non-zero values always use at least one cell.
+func scale(value int, largest int, width int) int {
+	if value <= 0 || largest <= 0 {
+		return 0
+	}
+
+	return (value*width + largest - 1) / largest
+}
+
+func (series Series) draw(cells int) string {
+	if cells == 0 {
+		return ""
+	}
+
+	if color.NoColor || len(series.Style) == 0 {
+		return strings.Repeat(series.Symbol, cells)
+	}
+
+	return series.Style.Sprint(strings.Repeat("█", cells))
+}
+
+func (series Series) legend() string {
+	return series.draw(1) + " " + series.Name
+}
+
+// Returns the largest value drawn on a single line.
+func (chart *Chart) largest() int {
+	largest := 0
+
+	for _, bar := range chart.Bars {
+		total := 0
+		for _, value := range bar.Values {
+			if chart.Stacked {
+				total += value
+			} else {
+				total = value
+			}
+
+			largest = max(largest, total)
+		}
+	}
+
+	return largest
+}
+
+func printBar(label string, bar string, values []string) {
+	parts := []string{label}
+	if bar != "" {
+		parts = append(parts, bar)
+	}
+
+	fmt.Println(strings.Join(append(parts, values...), " "))
+}
+
+func (chart *Chart) Print() {
+	labelWidth := 0
+	for _, bar := range chart.Bars {
+		labelWidth = max(labelWidth, runewidth.StringWidth(bar.Label))
+	}
+
+	maxValue := chart.largest()
+	valueWidth := len(strconv.Itoa(maxValue))
+
+	width, ok := TerminalWidth()
+	if !ok {
+		width = defaultChartWidth
+	}
+
+	// Leave room for the label and the values after the bar
+	barWidth := width - labelWidth - 1
+	if chart.Stacked {
+		barWidth -= (valueWidth + 1) * len(chart.Series)
+	} else {
+		barWidth -= valueWidth + 1
+	}
+
+	barWidth = max(barWidth, 1)
+
+	var legend []string
+	for _, series := range chart.Series {
+		legend = append(legend, series.legend())
+	}
+
+	fmt.Println(strings.Join(legend, "  "))
+	fmt.Println()
+
+	for _, bar := range chart.Bars {
+		label := runewidth.FillRight(bar.Label, labelWidth)
+
+		if chart.Stacked {
+			var line strings.Builder
+			var values []string
+			total := 0
+
+			for i, series := range chart.Series {
+				// Scale the running total so rounding doesn't change the
+				// length of the full bar.
+				cells := scale(total+bar.Values[i], maxValue, barWidth) - scale(total, maxValue, barWidth)
+				total += bar.Values[i]
+
+				line.WriteString(series.draw(cells))
+				values = append(values, strconv.Itoa(bar.Values[i]))
+			}
+
+			printBar(label, line.String(), values)
+			continue
+		}
+
+		for i, series := range chart.Series {
+			if i > 0 {
+				label = strings.Repeat(" ", labelWidth)
+			}
+
+			line := series.draw(scale(bar.Values[i], maxValue, barWidth))
+			printBar(label, line, []string{strconv.Itoa(bar.Values[i])})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScale(t *testing.T) {
+	assert.Equal(t, 0, scale(0, 10, 50))
+	assert.Equal(t, 50, scale(10, 10, 50))
+	assert.Equal(t, 25, scale(5, 10, 50))
+	assert.Equal(t, 1, scale(1, 1000, 50))
+	assert.Equal(t, 0, scale(5, 0, 50))
+}
+
+func TestChartLargest(t *testing.T) {
+	chart := Chart{
+		Series: []Series{{Name: "a"}, {Name: "b"}},
+		Bars: []Bar{
+			{Label: "1", Values: []int{3, 4}},
+			{Label: "2", Values: []int{5, 1}},
+		},
+	}
+
+	assert.Equal(t, 5, chart.largest())
+
+	chart.Stacked = true
+	assert.Equal(t, 7, chart.largest())
+}
@@ -0,0 +1,159 @@
+package printer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatNDJSON   Format = "ndjson"
+	FormatCSV      Format = "csv"
+	FormatTSV      Format = "tsv"
+	FormatMarkdown Format = "markdown"
+)
+
+func FormatFromStr(str string) (Format, bool) {
+	switch Format(str) {
+	case FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatMarkdown:
+		return Format(str), true
+	case "md":
+		return FormatMarkdown, true
+	default:
+		return "", false
+	}
+}
+
+// Returns true if the format is structured data rather than a table of cells.
+func (f Format) IsData() bool {
+	return f == FormatJSON || f == FormatNDJSON
+}
+
+// Prints a list of values as a JSON array, or as one JSON document per line
+// when using the `ndjson` format.
+func JSON[T any](format Format, values []T) {
+	if err := writeJSON(os.Stdout, format, values); err != nil {
+		Error(err)
+	}
+}
+
+// Prints a single value as JSON, on a single line when using the `ndjson`
+// format.
+func JSONValue(format Format, value any) {
+	encoder := json.NewEncoder(os.Stdout)
+	if format != FormatNDJSON {
+		encoder.SetIndent("", "  ")
+	}
+
+	if err := encoder.Encode(value); err != nil {
+		Error(fmt.Errorf("Failed to encode JSON: %w", err))
+	}
+}
+
+func writeJSON[T any](w io.Writer, format Format, values []T) error {
+	if format == FormatNDJSON {
+		encoder := json.NewEncoder(w)
+
+		for _, value := range values {
+			if err := encoder.Encode(value); err != nil {
+				return fmt.Errorf("Failed to encode JSON: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	// Always print an array, even when empty, so consumers don't have to
+	// special case `null`.
+	if values == nil {
+		values = []T{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(values); err != nil {
+		return fmt.Errorf("Failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// Prints the table using the given format. Unlike the default table output,
+// the machine-readable formats always include every column so that consumers
+// can rely on the column positions.
+func (table *Table) PrintAs(format Format) {
+	var err error
+
+	switch format {
+	case FormatCSV:
+		err = table.writeDelimited(os.Stdout, ',')
+	case FormatTSV:
+		err = table.writeDelimited(os.Stdout, '\t')
+	case FormatMarkdown:
+		err = table.writeMarkdown(os.Stdout)
+	default:
+		table.Print()
+	}
+
+	if err != nil {
+		Error(err)
+	}
+}
+
+func (table *Table) writeDelimited(w io.Writer, separator rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = separator
+
+	if err := writer.Write(table.Columns); err != nil {
+		return fmt.Errorf("Failed to write table: %w", err)
+	}
+
+	for _, row := range table.Rows {
+		if err := writer.Write(row.Cells); err != nil {
+			return fmt.Errorf("Failed to write table: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func escapeMarkdown(cell string) string {
+	cell = strings.ReplaceAll(cell, "|", "\\|")
+	return strings.ReplaceAll(cell, "\n", " ")
+}
+
+func (table *Table) writeMarkdown(w io.Writer) error {
+	var lines []string
+	var header []string
+	var divider []string
+
+	for _, col := range table.Columns {
+		header = append(header, escapeMarkdown(col))
+		divider = append(divider, "---")
+	}
+
+	lines = append(lines, "| "+strings.Join(header, " | ")+" |")
+	lines = append(lines, "| "+strings.Join(divider, " | ")+" |")
+
+	for _, row := range table.Rows {
+		var cells []string
+
+		for _, cell := range row.Cells {
+			cells = append(cells, escapeMarkdown(cell))
+		}
+
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
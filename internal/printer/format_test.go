@@ -0,0 +1,61 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var table = Table{
+	Columns: []string{"ID", "Tags", "Title"},
+	Rows: []Row{
+		{Cells: []string{"1", "", "Buy milk"}},
+		{Cells: []string{"2", "work", "Fix | pipes, commas"}},
+	},
+}
+
+func TestFormatFromStr(t *testing.T) {
+	format, ok := FormatFromStr("json")
+	assert.True(t, ok)
+	assert.Equal(t, format, FormatJSON)
+
+	format, ok = FormatFromStr("md")
+	assert.True(t, ok)
+	assert.Equal(t, format, FormatMarkdown)
+
+	_, ok = FormatFromStr("xml")
+	assert.False(t, ok)
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, table.writeDelimited(&buf, ','))
+	assert.Equal(t, buf.String(), "ID,Tags,Title\n1,,Buy milk\n2,work,\"Fix | pipes, commas\"\n")
+}
+
+func TestTSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, table.writeDelimited(&buf, '\t'))
+	assert.Equal(t, buf.String(), "ID\tTags\tTitle\n1\t\tBuy milk\n2\twork\tFix | pipes, commas\n")
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, table.writeMarkdown(&buf))
+	assert.Equal(t, buf.String(), "| ID | Tags | Title |\n| --- | --- | --- |\n| 1 |  | Buy milk |\n| 2 | work | Fix \\| pipes, commas |\n")
+}
+
+func TestJSON(t *testing.T) {
+	type item struct {
+		Id int `json:"id"`
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeJSON[item](&buf, FormatJSON, nil))
+	assert.Equal(t, buf.String(), "[]\n")
+
+	buf.Reset()
+	assert.NoError(t, writeJSON(&buf, FormatNDJSON, []item{{Id: 1}, {Id: 2}}))
+	assert.Equal(t, buf.String(), "{\"id\":1}\n{\"id\":2}\n")
+}
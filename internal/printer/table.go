@@ -8,24 +8,111 @@ import (
 	"github.com/mattn/go-runewidth"
 )
 
+type Overflow string
+
+const (
+	// Wrap long cells onto continuation lines
+	OverflowWrap Overflow = "wrap"
+	// Truncate long cells with an ellipsis
+	OverflowTruncate Overflow = "truncate"
+	// Print the full row even if it is wider than the terminal
+	OverflowNone Overflow = "none"
+)
+
+func OverflowFromStr(str string) (Overflow, bool) {
+	switch Overflow(str) {
+	case OverflowWrap, OverflowTruncate, OverflowNone:
+		return Overflow(str), true
+	default:
+		return "", false
+	}
+}
+
+// The minimum width the flexible column will shrink to before the table is
+// allowed to overflow the terminal.
+const minFlexWidth = 10
+
+// Markers that highlight text in table cells, such as search matches. The
+// markers don't take up any width and are removed when colors are disabled.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// Continuation lines of wrapped cells are indented to make it clear they
+// belong to the previous line.
+const wrapIndent = "  "
+
 type Row struct {
 	Cells     []string
 	Highlight bool
+	// Additional style applied on top of the row shading, typically from
+	// `RuleStyle`.
+	Style Style
 }
 
 type Table struct {
 	Columns []string
 	Rows    []Row
+	// How to handle rows that are wider than the terminal. The last column of
+	// the table is the flexible column that will be wrapped or truncated to fit
+	// the terminal width. Defaults to wrapping.
+	Overflow Overflow
 }
 
 // Special implementation of string padding to account for unicode string width
 func pad(str string, w int) string {
-	return str + strings.Repeat(" ", w-runewidth.StringWidth(str))
+	return str + strings.Repeat(" ", max(0, w-runewidth.StringWidth(str)))
 }
 
-func (table *Table) Print() {
+// Wraps text on word boundaries so that no line is wider than the given width.
+// Words that are wider than the width are split across lines.
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+
+	// Ends the current line and starts an indented continuation line
+	flush := func() {
+		lines = append(lines, line)
+		line = wrapIndent
+	}
+
+	for _, word := range strings.Fields(text) {
+		if strings.TrimSpace(line) != "" {
+			if runewidth.StringWidth(line+" "+word) <= width {
+				line += " " + word
+				continue
+			}
+
+			flush()
+		}
+
+		// Split words that are too wide to fit on a line by themselves
+		for runewidth.StringWidth(line+word) > width {
+			head := runewidth.Truncate(word, width-runewidth.StringWidth(line), "")
+			if head == "" {
+				break
+			}
+
+			line += head
+			word = word[len(head):]
+			flush()
+		}
+
+		line += word
+	}
+
+	if strings.TrimSpace(line) != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// Calculates the width of each column, ignoring empty columns. If the table
+// would be wider than the terminal, the last column is shrunk to fit.
+func (table *Table) widths(termWidth int, constrained bool) []int {
 	widths := make([]int, len(table.Columns))
-	boldUnderline := color.New().Add(color.Bold, color.Underline).SprintFunc()
 
 	// Find the maximum width of each column
 	for _, row := range table.Rows {
@@ -43,11 +130,70 @@ func (table *Table) Print() {
 		}
 	}
 
+	if !constrained || table.Overflow == OverflowNone || len(widths) == 0 {
+		return widths
+	}
+
+	total := 0
+	visible := 0
+	for _, width := range widths {
+		if width > 0 {
+			total += width
+			visible++
+		}
+	}
+
+	// Account for the space between columns
+	total += max(0, visible-1)
+
+	flex := len(widths) - 1
+	if total > termWidth && widths[flex] > 0 {
+		minWidth := max(min(minFlexWidth, widths[flex]), len(table.Columns[flex]))
+		widths[flex] = max(minWidth, widths[flex]-(total-termWidth))
+	}
+
+	return widths
+}
+
+// Splits a row into the lines that should be printed, wrapping or truncating
+// the last cell to fit in the column width.
+func (table *Table) lines(row Row, widths []int) [][]string {
+	flex := len(widths) - 1
+	cell := row.Cells[flex]
+
+	if runewidth.StringWidth(cell) <= widths[flex] {
+		return [][]string{row.Cells}
+	}
+
+	if table.Overflow == OverflowTruncate {
+		cells := append([]string{}, row.Cells...)
+		cells[flex] = runewidth.Truncate(cell, widths[flex], "…")
+		return [][]string{cells}
+	}
+
+	var lines [][]string
+	for i, text := range wrap(cell, widths[flex]) {
+		cells := make([]string, len(row.Cells))
+		if i == 0 {
+			copy(cells, row.Cells)
+		}
+
+		cells[flex] = text
+		lines = append(lines, cells)
+	}
+
+	return lines
+}
+
+func (table *Table) Print() {
+	termWidth, constrained := TerminalWidth()
+	widths := table.widths(termWidth, constrained)
+
 	// Create the header row, skipping empty columns
 	var header []string
 	for i, col := range table.Columns {
 		if widths[i] > 0 {
-			header = append(header, boldUnderline(pad(col, widths[i])))
+			header = append(header, theme.Header.Sprint(pad(col, widths[i])))
 		}
 	}
 
@@ -67,22 +213,44 @@ func (table *Table) Print() {
 	}
 
 	for i, row := range table.Rows {
-		var cells []string
+		style := Style{}
+		if row.Highlight {
+			style = append(style, theme.Active...)
+		} else if i%2 == 1 {
+			style = append(style, theme.Zebra...)
+		}
 
-		for i, cell := range row.Cells {
-			if widths[i] > 0 {
-				cells = append(cells, pad(cell, widths[i]))
+		style = append(style, row.Style...)
+
+		// Every line of a wrapped row uses the same color so the alternating
+		// row shading is preserved.
+		for _, lineCells := range table.lines(row, widths) {
+			var cells []string
+
+			for i, cell := range lineCells {
+				if widths[i] > 0 {
+					cells = append(cells, pad(cell, widths[i]))
+				}
 			}
-		}
 
-		line := strings.Join(cells, " ")
-		if row.Highlight {
-			// color.BGRGB(129, 97, 170)
-			color.New(color.BgMagenta).Println(line)
-		} else if i%2 == 0 {
-			fmt.Println(line)
-		} else {
-			color.New(color.BgBlack).Println(line)
+			style.println(highlight(strings.Join(cells, " "), style))
 		}
 	}
 }
+
+// Replaces the highlight markers in a line with the match style, restoring the
+// row style after each highlight.
+func highlight(line string, row Style) string {
+	if !strings.Contains(line, HighlightStart) {
+		return line
+	}
+
+	if color.NoColor || len(theme.Match) == 0 {
+		return strings.NewReplacer(HighlightStart, "", HighlightEnd, "").Replace(line)
+	}
+
+	return strings.NewReplacer(
+		HighlightStart, theme.Match.sequence(),
+		HighlightEnd, "\x1b[0m"+row.sequence(),
+	).Replace(line)
+}
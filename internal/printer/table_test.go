@@ -0,0 +1,67 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, wrap("", 10), []string{""})
+	assert.Equal(t, wrap("Buy milk", 10), []string{"Buy milk"})
+	assert.Equal(t,
+		wrap("Buy milk and eggs from the store", 10),
+		[]string{"Buy milk", "  and eggs", "  from the", "  store"},
+	)
+	assert.Equal(t,
+		wrap("Refactor abcdefghijklmnop", 10),
+		[]string{"Refactor", "  abcdefgh", "  ijklmnop"},
+	)
+}
+
+func TestWidthsShrinkLastColumn(t *testing.T) {
+	table := Table{
+		Columns: []string{"ID", "Title"},
+		Rows: []Row{
+			{Cells: []string{"1", "Buy milk and eggs from the store"}},
+		},
+	}
+
+	assert.Equal(t, table.widths(0, false), []int{2, 32})
+	assert.Equal(t, table.widths(20, true), []int{2, 17})
+	assert.Equal(t, table.widths(5, true), []int{2, 10})
+
+	table.Overflow = OverflowNone
+	assert.Equal(t, table.widths(20, true), []int{2, 32})
+}
+
+func TestLinesTruncate(t *testing.T) {
+	table := Table{
+		Columns:  []string{"ID", "Title"},
+		Overflow: OverflowTruncate,
+	}
+
+	row := Row{Cells: []string{"1", "Buy milk and eggs"}}
+	assert.Equal(t, table.lines(row, []int{2, 10}), [][]string{{"1", "Buy milk …"}})
+
+	table.Overflow = OverflowWrap
+	assert.Equal(t,
+		table.lines(row, []int{2, 10}),
+		[][]string{{"1", "Buy milk"}, {"", "  and eggs"}},
+	)
+}
+
+func TestHighlight(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	line := "1 Fix " + HighlightStart + "login" + HighlightEnd + " page"
+
+	color.NoColor = true
+	assert.Equal(t, "1 Fix login page", highlight(line, Style{}))
+
+	color.NoColor = false
+	assert.Equal(t, "1 Fix \x1b[1;33mlogin\x1b[0m\x1b[40m page", highlight(line, Style{color.BgBlack}))
+	assert.Equal(t, "plain", highlight("plain", Style{}))
+}
@@ -0,0 +1,11 @@
+package printer
+
+import "github.com/mskelton/tsk/internal/terminal"
+
+// Returns the width of the terminal attached to stdout. The second return
+// value is false if stdout is not a terminal, in which case output should not
+// be constrained to any width.
+func TerminalWidth() (int, bool) {
+	width, _, ok := terminal.Size()
+	return width, ok
+}
@@ -0,0 +1,301 @@
+package printer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// A list of SGR attributes applied to printed text
+type Style []color.Attribute
+
+type Theme struct {
+	// The style of table headers
+	Header Style
+	// The style of every other row in a table
+	Zebra Style
+	// The style of active tasks
+	Active Style
+	// Styles applied to rows based on the priority of the task
+	Priority map[string]Style
+	// Styles applied to rows based on the tags of the task
+	Tags map[string]Style
+	// The style of pending (or added) tasks in charts
+	ChartPending Style
+	// The style of completed tasks in charts
+	ChartDone Style
+	// The style of days with tasks in calendars
+	CalendarTask Style
+	// The style of the current day in calendars
+	CalendarToday Style
+	// The style of highlighted text in tables, such as search matches
+	Match Style
+	// Disables all colors when true
+	NoColor bool
+}
+
+var attributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"dim":       color.Faint,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"blink":     color.BlinkSlow,
+	"reverse":   color.ReverseVideo,
+}
+
+var colors = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+	"gray":    color.FgHiBlack,
+}
+
+// The offset between foreground and background colors
+const bgOffset = color.BgBlack - color.FgBlack
+
+// The offset between normal and bright colors
+const brightOffset = color.FgHiBlack - color.FgBlack
+
+func parseColor(token string) (Style, bool) {
+	if c, ok := colors[token]; ok {
+		return Style{c}, true
+	}
+
+	if name, ok := strings.CutPrefix(token, "bright_"); ok {
+		if c, ok := colors[name]; ok && c != color.FgHiBlack {
+			return Style{c + brightOffset}, true
+		}
+	}
+
+	// 256-color palette (e.g., `color214`)
+	if index, ok := strings.CutPrefix(token, "color"); ok {
+		if n, err := strconv.Atoi(index); err == nil && n >= 0 && n <= 255 {
+			return Style{38, 5, color.Attribute(n)}, true
+		}
+	}
+
+	// Truecolor (e.g., `#8161aa`)
+	if hex, ok := strings.CutPrefix(token, "#"); ok && len(hex) == 6 {
+		if n, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return Style{
+				38, 2,
+				color.Attribute(n >> 16 & 0xff),
+				color.Attribute(n >> 8 & 0xff),
+				color.Attribute(n & 0xff),
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// Parses a style specification such as `bold red on_black`. Colors can be
+// one of the named colors, a 256-color palette index (`color214`), or a
+// truecolor hex value (`#8161aa`). Prefixing a color with `on_` sets the
+// background color.
+func ParseStyle(spec string) (Style, error) {
+	style := Style{}
+
+	tokens := strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+
+	for _, token := range tokens {
+		if token == "none" {
+			continue
+		}
+
+		if attr, ok := attributes[token]; ok {
+			style = append(style, attr)
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(token, "on_"); ok {
+			if c, ok := parseColor(name); ok {
+				// Extended colors use `48` rather than `38` for the background
+				if c[0] == 38 {
+					c[0] = 48
+				} else {
+					c[0] += bgOffset
+				}
+
+				style = append(style, c...)
+				continue
+			}
+		} else if c, ok := parseColor(token); ok {
+			style = append(style, c...)
+			continue
+		}
+
+		return nil, fmt.Errorf("Invalid color \"%s\"", token)
+	}
+
+	return style, nil
+}
+
+func (s Style) Sprint(text string) string {
+	if len(s) == 0 {
+		return text
+	}
+
+	return color.New(s...).Sprint(text)
+}
+
+// Returns the escape sequence that applies the style.
+func (s Style) sequence() string {
+	if len(s) == 0 {
+		return ""
+	}
+
+	codes := make([]string, len(s))
+	for i, attr := range s {
+		codes[i] = strconv.Itoa(int(attr))
+	}
+
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func (s Style) println(text string) {
+	if len(s) == 0 {
+		fmt.Println(text)
+	} else {
+		color.New(s...).Println(text)
+	}
+}
+
+func ThemeFromStr(name string) (Theme, bool) {
+	header := Style{color.Bold, color.Underline}
+	priority := map[string]Style{"H": {color.Bold}}
+
+	switch name {
+	case "dark":
+		return Theme{
+			Header:        header,
+			Zebra:         Style{color.BgBlack},
+			Active:        Style{color.BgMagenta},
+			Priority:      priority,
+			Tags:          map[string]Style{},
+			ChartPending:  Style{color.FgRed},
+			ChartDone:     Style{color.FgGreen},
+			CalendarTask:  Style{color.Bold, color.FgYellow},
+			CalendarToday: Style{color.ReverseVideo},
+			Match:         Style{color.Bold, color.FgYellow},
+		}, true
+
+	case "light":
+		return Theme{
+			Header:        header,
+			Zebra:         Style{48, 5, 254},
+			Active:        Style{48, 5, 183},
+			Priority:      priority,
+			Tags:          map[string]Style{},
+			ChartPending:  Style{38, 5, 160},
+			ChartDone:     Style{38, 5, 28},
+			CalendarTask:  Style{color.Bold, 38, 5, 166},
+			CalendarToday: Style{color.ReverseVideo},
+			Match:         Style{color.Bold, 38, 5, 166},
+		}, true
+
+	case "none":
+		return Theme{
+			Priority: map[string]Style{},
+			Tags:     map[string]Style{},
+			NoColor:  true,
+		}, true
+
+	case "custom":
+		return Theme{
+			Priority: map[string]Style{},
+			Tags:     map[string]Style{},
+		}, true
+
+	default:
+		return Theme{}, false
+	}
+}
+
+// Sets the style for a color key such as `header`, `priority.H`, or
+// `tag.urgent`.
+func (t *Theme) SetColor(key string, spec string) error {
+	style, err := ParseStyle(spec)
+	if err != nil {
+		return err
+	}
+
+	if name, ok := strings.CutPrefix(key, "priority."); ok && name != "" {
+		t.Priority[name] = style
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(key, "tag."); ok && name != "" {
+		t.Tags[name] = style
+		return nil
+	}
+
+	switch key {
+	case "header":
+		t.Header = style
+	case "zebra":
+		t.Zebra = style
+	case "active":
+		t.Active = style
+	case "chart.pending":
+		t.ChartPending = style
+	case "chart.done":
+		t.ChartDone = style
+	case "calendar.task":
+		t.CalendarTask = style
+	case "calendar.today":
+		t.CalendarToday = style
+	case "match":
+		t.Match = style
+	default:
+		return fmt.Errorf("Invalid color key \"%s\"", key)
+	}
+
+	return nil
+}
+
+var theme, _ = ThemeFromStr("dark")
+
+func init() {
+	// The color package already disables colors when `NO_COLOR` is set, but
+	// does not support forcing colors when stdout is not a terminal.
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" && os.Getenv("NO_COLOR") == "" {
+		color.NoColor = false
+	}
+}
+
+func CurrentTheme() Theme {
+	return theme
+}
+
+func SetTheme(t Theme) {
+	theme = t
+
+	if t.NoColor {
+		color.NoColor = true
+	}
+}
+
+// Returns the style for a row based on the color rules for the priority and
+// tags of the task. Tag rules take precedence over priority rules.
+func RuleStyle(priority string, tags []string) Style {
+	style := Style{}
+	style = append(style, theme.Priority[priority]...)
+
+	for _, tag := range tags {
+		style = append(style, theme.Tags[tag]...)
+	}
+
+	return style
+}
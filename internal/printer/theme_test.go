@@ -0,0 +1,63 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStyle(t *testing.T) {
+	style, err := ParseStyle("bold red on_black")
+	assert.NoError(t, err)
+	assert.Equal(t, style, Style{color.Bold, color.FgRed, color.BgBlack})
+
+	style, err = ParseStyle("bright_green,on_bright_blue")
+	assert.NoError(t, err)
+	assert.Equal(t, style, Style{color.FgHiGreen, color.BgHiBlue})
+
+	style, err = ParseStyle("color214 on_color17")
+	assert.NoError(t, err)
+	assert.Equal(t, style, Style{38, 5, 214, 48, 5, 17})
+
+	style, err = ParseStyle("#8161aa on_#000000")
+	assert.NoError(t, err)
+	assert.Equal(t, style, Style{38, 2, 129, 97, 170, 48, 2, 0, 0, 0})
+
+	style, err = ParseStyle("none")
+	assert.NoError(t, err)
+	assert.Equal(t, style, Style{})
+
+	_, err = ParseStyle("bold purple")
+	assert.EqualError(t, err, "Invalid color \"purple\"")
+
+	_, err = ParseStyle("color256")
+	assert.Error(t, err)
+}
+
+func TestSetColor(t *testing.T) {
+	theme, ok := ThemeFromStr("custom")
+	assert.True(t, ok)
+
+	assert.NoError(t, theme.SetColor("header", "underline"))
+	assert.NoError(t, theme.SetColor("tag.urgent", "red"))
+	assert.NoError(t, theme.SetColor("priority.H", "bold"))
+	assert.Error(t, theme.SetColor("foo", "red"))
+
+	assert.Equal(t, theme.Header, Style{color.Underline})
+	assert.Equal(t, theme.Tags["urgent"], Style{color.FgRed})
+	assert.Equal(t, theme.Priority["H"], Style{color.Bold})
+}
+
+func TestRuleStyle(t *testing.T) {
+	original := theme
+	defer SetTheme(original)
+
+	custom, _ := ThemeFromStr("custom")
+	custom.Priority["H"] = Style{color.Bold}
+	custom.Tags["urgent"] = Style{color.FgRed}
+	SetTheme(custom)
+
+	assert.Equal(t, RuleStyle("L", []string{"home"}), Style{})
+	assert.Equal(t, RuleStyle("H", []string{"urgent"}), Style{color.Bold, color.FgRed})
+}
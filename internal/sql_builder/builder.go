@@ -13,9 +13,20 @@ type Operator string
 const (
 	Eq      Operator = "="
 	Neq     Operator = "!="
+	Lte     Operator = "<="
+	Gt      Operator = ">"
 	In      Operator = "in"
 	Like    Operator = "like"
 	NotLike Operator = "not like"
+	Between Operator = "between"
+	Regexp  Operator = "regexp"
+	Is      Operator = "is"
+	IsNot   Operator = "is not"
+	// Operators for subqueries, which are used without a key
+	Exists    Operator = "exists"
+	NotExists Operator = "not exists"
+	// Full-text search operator for FTS5 tables
+	Match Operator = "match"
 )
 
 type Filter struct {
@@ -61,7 +72,12 @@ func (b *Builder) Filter(filter Filter) *Builder {
 		b.query += " and "
 	}
 
-	b.query += fmt.Sprintf("%s %s %s", filter.Key, filter.Operator, filter.Value)
+	if filter.Key == "" {
+		b.query += fmt.Sprintf("%s %s", filter.Operator, filter.Value)
+	} else {
+		b.query += fmt.Sprintf("%s %s %s", filter.Key, filter.Operator, filter.Value)
+	}
+
 	return b
 }
 
@@ -77,6 +93,11 @@ func (b *Builder) Set(fields string) *Builder {
 	return b
 }
 
+func (b *Builder) OrderBy(columns string) *Builder {
+	b.query += " order by " + columns
+	return b
+}
+
 func (b *Builder) SQL() string {
 	return b.query
 }
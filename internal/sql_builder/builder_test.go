@@ -63,3 +63,31 @@ func TestFilterMultipleConditions(t *testing.T) {
 
 	assert.Equal(t, sql, "select id, name from users where id = 1 and name like 'John'")
 }
+
+func TestFilterSubquery(t *testing.T) {
+	sql := sql_builder.New().
+		Select("id, name").
+		From("users").
+		Filter(sql_builder.Filter{
+			Operator: sql_builder.NotExists,
+			Value:    "(select 1 from roles where roles.user_id = users.id)",
+		}).
+		SQL()
+
+	assert.Equal(t, sql, "select id, name from users where not exists (select 1 from roles where roles.user_id = users.id)")
+}
+
+func TestOrderBy(t *testing.T) {
+	sql := sql_builder.New().
+		Select("id, name").
+		From("users").
+		Filter(sql_builder.Filter{
+			Key:      "users",
+			Operator: sql_builder.Match,
+			Value:    "?",
+		}).
+		OrderBy("rank").
+		SQL()
+
+	assert.Equal(t, sql, "select id, name from users where users match ? order by rank")
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+)
+
+var doneFilter = sql_builder.Filter{
+	Key:      "tasks.data ->> '$.status'",
+	Operator: sql_builder.Eq,
+	Value:    "'done'",
+}
+
+// Tasks completed before `completed_at` was recorded use the time they were
+// last updated instead.
+const completedAtKey = "coalesce(tasks.data ->> '$.completed_at', tasks.data ->> '$.updated_at')"
+
+// Moves the completed tasks matching the filters that were completed before
+// the cutoff to the archive, releasing their short ids. A zero cutoff archives
+// every matching completed task. Archiving is local to this device, so it is
+// not synced and hooks are not run.
+func Archive(filters []sql_builder.Filter, cutoff time.Time) ([]Task, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to archive tasks: %w", err)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to archive tasks: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	filters = append(append([]sql_builder.Filter{doneFilter}, beforeFilter(completedAtKey, cutoff)...), filters...)
+	tasks, err := queryTasks(tx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to archive tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		return []Task{}, nil
+	}
+
+	ids := TaskIdsFilter(tasks)
+	queries := []string{
+		fmt.Sprintf("INSERT OR REPLACE INTO archive (id, template_id, data) SELECT id, template_id, data FROM tasks WHERE id in %s", ids.Value),
+		fmt.Sprintf("DELETE FROM tasks WHERE id in %s", ids.Value),
+		fmt.Sprintf("DELETE FROM assignments WHERE task_id in %s", ids.Value),
+	}
+
+	for _, query := range queries {
+		if os.Getenv("DEBUG") != "" {
+			log.Println(query)
+		}
+
+		if _, err := tx.Exec(query); err != nil {
+			return nil, fmt.Errorf("Failed to archive tasks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to archive tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Gets the archived tasks matching the filters. Archived tasks don't have
+// short ids, so id filters never match them.
+func GetArchivedTasks(filters []sql_builder.Filter) ([]Task, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list archived tasks: %w", err)
+	}
+
+	// The archive is aliased as `tasks` so the same filters can be used for
+	// archived tasks as for other tasks.
+	builder := sql_builder.New().
+		Select("tasks.id, tasks.template_id, null, tasks.data").
+		From("archive as tasks")
+
+	for _, filter := range filters {
+		builder.Filter(filter)
+	}
+
+	if os.Getenv("DEBUG") != "" {
+		log.Println(builder.SQL())
+	}
+
+	rows, err := conn.Query(builder.SQL())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list archived tasks: %w", err)
+	}
+
+	defer rows.Close()
+	tasks := []Task{}
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list archived tasks: %w", err)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Marks a task as done at the completion time. A nil completion time leaves
+// `completed_at` unset, like tasks completed before it was recorded.
+func completeTask(t *testing.T, task Task, completedAt *time.Time, updatedAt time.Time) {
+	task.Status = TaskStatusDone
+	task.CompletedAt = completedAt
+	task.UpdatedAt = updatedAt
+
+	data, err := marshal(task)
+	assert.NoError(t, err)
+
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	_, err = conn.Exec("UPDATE tasks SET data = ? WHERE id = ?", data, task.Id)
+	assert.NoError(t, err)
+}
+
+func TestArchive(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -100)
+	recent := now.AddDate(0, 0, -5)
+
+	completeTask(t, addTask(t, "Completed long ago"), &old, now)
+	completeTask(t, addTask(t, "Completed recently"), &recent, old)
+	completeTask(t, addTask(t, "Updated long ago"), nil, old)
+	completeTask(t, addTask(t, "Updated recently"), nil, recent)
+	addTask(t, "Pending")
+
+	// The completion time is used for the cutoff, falling back to the time the
+	// task was last updated
+	archived, err := Archive(nil, now.AddDate(0, 0, -30))
+	assert.NoError(t, err)
+
+	var titles []string
+	for _, task := range archived {
+		titles = append(titles, task.Title)
+	}
+
+	assert.ElementsMatch(t, []string{"Completed long ago", "Updated long ago"}, titles)
+	assert.Equal(t, map[int]string{2: "Completed recently", 4: "Updated recently", 5: "Pending"}, titlesByShortId(t))
+
+	// Archived tasks release their short ids
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	var count int
+	assert.NoError(t, conn.QueryRow("SELECT count(*) FROM assignments WHERE id in (1, 3)").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	archived, err = GetArchivedTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, archived, 2)
+
+	for _, task := range archived {
+		assert.Equal(t, 0, task.ShortId)
+		assert.Equal(t, TaskStatusDone, task.Status)
+	}
+
+	// A zero cutoff archives every completed task
+	archived, err = Archive(nil, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, archived, 2)
+	assert.Equal(t, map[int]string{5: "Pending"}, titlesByShortId(t))
+
+	archived, err = GetArchivedTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, archived, 4)
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+var ErrBlueprintNotFound = errors.New("Blueprint not found")
+
+// A named preset used to create similar tasks, such as release checklists.
+// Blueprints are separate from recurrence templates and are not synced.
+type Blueprint struct {
+	Name string `json:"name"`
+	// The default title of tasks created from the blueprint (if any)
+	Title      string            `json:"title,omitempty"`
+	Priority   string            `json:"priority,omitempty"`
+	Project    string            `json:"project,omitempty"`
+	Tags       []string          `json:"tags"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// The checklist items added unchecked to each task
+	Checklist []string `json:"checklist,omitempty"`
+	// Dates relative to the day the task is created (e.g., `3d` or `fri`)
+	Due       string `json:"due,omitempty"`
+	Scheduled string `json:"scheduled,omitempty"`
+	Wait      string `json:"wait,omitempty"`
+}
+
+// Applies the presets of the blueprint to a task. Tags, attributes, and
+// checklist items are added to those of the task, and relative dates are
+// resolved from now.
+func (b Blueprint) Apply(task *Task, now time.Time) error {
+	if b.Title != "" {
+		task.Title = b.Title
+	}
+
+	if b.Priority != "" {
+		task.Priority = b.Priority
+	}
+
+	if b.Project != "" {
+		task.Project = b.Project
+	}
+
+	for _, tag := range b.Tags {
+		if !slices.Contains(task.Tags, tag) {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	if len(b.Attributes) > 0 {
+		if task.Attributes == nil {
+			task.Attributes = make(map[string]string)
+		}
+
+		maps.Copy(task.Attributes, b.Attributes)
+	}
+
+	for _, item := range b.Checklist {
+		task.Checklist = append(task.Checklist, ChecklistItem{Text: item})
+	}
+
+	dates := []struct {
+		value string
+		field **time.Time
+	}{
+		{b.Due, &task.Due},
+		{b.Scheduled, &task.Scheduled},
+		{b.Wait, &task.Wait},
+	}
+
+	for _, date := range dates {
+		if date.value == "" {
+			continue
+		}
+
+		t, err := utils.ParseDate(date.value, now)
+		if err != nil {
+			return fmt.Errorf("Invalid blueprint \"%s\": %w", b.Name, err)
+		}
+
+		*date.field = &t
+	}
+
+	return nil
+}
+
+// Saves a blueprint, replacing any existing blueprint with the same name.
+// Returns true if an existing blueprint was replaced.
+func SaveBlueprint(blueprint Blueprint) (bool, error) {
+	conn, err := connect()
+	if err != nil {
+		return false, fmt.Errorf("Failed to save blueprint: %w", err)
+	}
+
+	data, err := json.Marshal(blueprint)
+	if err != nil {
+		return false, fmt.Errorf("Failed to save blueprint: %w", err)
+	}
+
+	var count int
+	err = conn.QueryRow("SELECT count(*) FROM blueprints WHERE name = ?", blueprint.Name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("Failed to save blueprint: %w", err)
+	}
+
+	_, err = conn.Exec("INSERT OR REPLACE INTO blueprints (name, data) VALUES (?, ?)", blueprint.Name, data)
+	if err != nil {
+		return false, fmt.Errorf("Failed to save blueprint: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+// Gets a blueprint by name, returning `ErrBlueprintNotFound` if it doesn't
+// exist.
+func GetBlueprint(name string) (Blueprint, error) {
+	var blueprint Blueprint
+
+	conn, err := connect()
+	if err != nil {
+		return blueprint, fmt.Errorf("Failed to get blueprint: %w", err)
+	}
+
+	var data []byte
+	err = conn.QueryRow("SELECT data FROM blueprints WHERE name = ?", name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return blueprint, ErrBlueprintNotFound
+	} else if err != nil {
+		return blueprint, fmt.Errorf("Failed to get blueprint: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &blueprint); err != nil {
+		return blueprint, fmt.Errorf("Failed to get blueprint: %w", err)
+	}
+
+	return blueprint, nil
+}
+
+// Lists all blueprints sorted by name.
+func ListBlueprints() ([]Blueprint, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list blueprints: %w", err)
+	}
+
+	rows, err := conn.Query("SELECT data FROM blueprints ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list blueprints: %w", err)
+	}
+
+	defer rows.Close()
+	blueprints := []Blueprint{}
+
+	for rows.Next() {
+		var data []byte
+		var blueprint Blueprint
+
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("Failed to list blueprints: %w", err)
+		}
+
+		if err := json.Unmarshal(data, &blueprint); err != nil {
+			return nil, fmt.Errorf("Failed to list blueprints: %w", err)
+		}
+
+		blueprints = append(blueprints, blueprint)
+	}
+
+	return blueprints, nil
+}
+
+// Deletes a blueprint by name, returning `ErrBlueprintNotFound` if it doesn't
+// exist.
+func DeleteBlueprint(name string) error {
+	conn, err := connect()
+	if err != nil {
+		return fmt.Errorf("Failed to delete blueprint: %w", err)
+	}
+
+	res, err := conn.Exec("DELETE FROM blueprints WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("Failed to delete blueprint: %w", err)
+	}
+
+	if deleted, _ := res.RowsAffected(); deleted == 0 {
+		return ErrBlueprintNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlueprintApply(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	blueprint := Blueprint{
+		Name:       "release",
+		Priority:   "H",
+		Tags:       []string{"release", "work"},
+		Attributes: map[string]string{"team": "web"},
+		Checklist:  []string{"Bump version", "Publish"},
+		Due:        "3d",
+	}
+
+	task := Task{Title: "Release v1.4", Tags: []string{"work"}}
+	assert.NoError(t, blueprint.Apply(&task, now))
+
+	due := time.Date(2026, 10, 22, 0, 0, 0, 0, time.Local)
+	assert.Equal(t, Task{
+		Title:      "Release v1.4",
+		Priority:   "H",
+		Tags:       []string{"work", "release"},
+		Attributes: map[string]string{"team": "web"},
+		Checklist:  []ChecklistItem{{Text: "Bump version"}, {Text: "Publish"}},
+		Due:        &due,
+	}, task)
+
+	blueprint = Blueprint{Name: "broken", Wait: "someday"}
+	assert.EqualError(t, blueprint.Apply(&task, now), "Invalid blueprint \"broken\": Invalid date \"someday\"")
+}
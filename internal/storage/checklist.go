@@ -0,0 +1,42 @@
+package storage
+
+import "fmt"
+
+// A step in the checklist of a task
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Returns the number of checked items out of the items in the checklist.
+func (t Task) ChecklistProgress() Progress {
+	progress := Progress{Total: len(t.Checklist)}
+
+	for _, item := range t.Checklist {
+		if item.Done {
+			progress.Done++
+		}
+	}
+
+	return progress
+}
+
+// Checks or unchecks a checklist item by its number, starting at 1.
+func (t *Task) CheckItem(number int, done bool) error {
+	if number < 1 || number > len(t.Checklist) {
+		return fmt.Errorf("Task %d has no checklist item %d", t.ShortId, number)
+	}
+
+	t.Checklist[number-1].Done = done
+	return nil
+}
+
+// Removes a checklist item by its number, starting at 1.
+func (t *Task) RemoveItem(number int) error {
+	if number < 1 || number > len(t.Checklist) {
+		return fmt.Errorf("Task %d has no checklist item %d", t.ShortId, number)
+	}
+
+	t.Checklist = append(t.Checklist[:number-1], t.Checklist[number:]...)
+	return nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecklist(t *testing.T) {
+	task := Task{
+		ShortId: 12,
+		Checklist: []ChecklistItem{
+			{Text: "Bump version"},
+			{Text: "Tag release"},
+			{Text: "Publish"},
+		},
+	}
+
+	assert.Equal(t, Progress{Done: 0, Total: 3}, task.ChecklistProgress())
+
+	assert.NoError(t, task.CheckItem(1, true))
+	assert.NoError(t, task.CheckItem(3, true))
+	assert.Equal(t, Progress{Done: 2, Total: 3}, task.ChecklistProgress())
+
+	assert.NoError(t, task.CheckItem(3, false))
+	assert.Equal(t, "1/3", task.ChecklistProgress().String())
+
+	assert.EqualError(t, task.CheckItem(0, true), "Task 12 has no checklist item 0")
+	assert.EqualError(t, task.CheckItem(4, true), "Task 12 has no checklist item 4")
+
+	assert.NoError(t, task.RemoveItem(2))
+	assert.Equal(t, []ChecklistItem{
+		{Text: "Bump version", Done: true},
+		{Text: "Publish"},
+	}, task.Checklist)
+
+	assert.Error(t, task.RemoveItem(3))
+}
@@ -5,10 +5,15 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
+// A database connection or transaction that queries can be run against
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
 func getDBPath() (string, error) {
 	if url := os.Getenv("DATABASE_URL"); url != "" {
 		return url, nil
@@ -37,7 +42,7 @@ func connect() (*sql.DB, error) {
 		return nil, errors.New("Invalid database path")
 	}
 
-	conn, err := sql.Open("sqlite3", path)
+	conn, err := sql.Open(driverName, path)
 	if err != nil {
 		return nil, errors.New("Failed to connect to database")
 	}
@@ -49,6 +54,17 @@ func connect() (*sql.DB, error) {
 	        data TEXT NOT NULL
 	    );
 
+	    CREATE TABLE IF NOT EXISTS archive (
+	        id TEXT PRIMARY KEY,
+	        template_id INTEGER,
+	        data TEXT NOT NULL
+	    );
+
+	    CREATE TABLE IF NOT EXISTS blueprints (
+	        name TEXT PRIMARY KEY,
+	        data TEXT NOT NULL
+	    );
+
 	    CREATE TABLE IF NOT EXISTS templates (
 	        id TEXT PRIMARY KEY,
 	        data TEXT NOT NULL
@@ -58,6 +74,28 @@ func connect() (*sql.DB, error) {
 	        id INTEGER PRIMARY KEY,
 	        task_id TEXT NOT NULL
 	    );
+
+	    CREATE TABLE IF NOT EXISTS meta (
+	        key TEXT PRIMARY KEY,
+	        value TEXT NOT NULL
+	    );
+
+	    CREATE TABLE IF NOT EXISTS changes (
+	        seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	        task_id TEXT NOT NULL,
+	        field TEXT NOT NULL,
+	        value TEXT NOT NULL,
+	        timestamp INTEGER NOT NULL,
+	        replica TEXT NOT NULL
+	    );
+
+	    CREATE INDEX IF NOT EXISTS changes_task_field ON changes (task_id, field);
+	    CREATE INDEX IF NOT EXISTS changes_timestamp ON changes (timestamp);
+
+	    CREATE TABLE IF NOT EXISTS sync_state (
+	        replica TEXT PRIMARY KEY,
+	        position INTEGER NOT NULL
+	    );
 	`
 
 	_, err = conn.Exec(query)
@@ -65,5 +103,9 @@ func connect() (*sql.DB, error) {
 		return nil, errors.New("Migration failed")
 	}
 
+	if err := migrateSearch(conn); err != nil {
+		return nil, errors.New("Migration failed")
+	}
+
 	return conn, nil
 }
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Adds a task when run as a hook by TestHooksCanWrite, standing in for a hook
+// that runs tsk.
+func TestHookHelper(t *testing.T) {
+	if os.Getenv("TSK_HOOK_HELPER") == "" {
+		t.Skip("only run as a hook")
+	}
+
+	task := NewTask()
+	task.Title = "Added by hook"
+
+	if _, err := Add(task); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHooksCanWrite(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts require a POSIX shell")
+	}
+
+	test_utils.UseTempDB(t)
+
+	task := NewTask()
+	task.Title = "Buy milk"
+	task.Tags = []string{"home"}
+	_, err := Add(task)
+	assert.NoError(t, err)
+
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "tsk", "hooks")
+	script := fmt.Sprintf("#!/bin/sh\nTSK_HOOK_HELPER=1 %q -test.run='^TestHookHelper$' >&2\n", os.Args[0])
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+
+	for _, event := range []string{"on-modify", "on-delete"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, event), []byte(script), 0755))
+	}
+
+	filters := []sql_builder.Filter{TaskIdsFilter([]Task{task})}
+
+	_, err = Edit(filters, []QueryEdit{{Path: "status", Value: string(TaskStatusDone)}})
+	assert.NoError(t, err)
+
+	_, err = ReplaceTag(filters, "home", "")
+	assert.NoError(t, err)
+
+	task.Title = "Buy oat milk"
+	assert.NoError(t, Update(task))
+
+	_, err = Delete(filters)
+	assert.NoError(t, err)
+
+	_, err = Restore(filters)
+	assert.NoError(t, err)
+
+	count, err := Count(nil)
+	assert.NoError(t, err)
+	// One task is added by the hook for each change
+	assert.Equal(t, 6, count)
+}
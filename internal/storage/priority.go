@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The priorities from highest to lowest, configured with the `priorities=`
+// config override.
+var priorities = []string{"H", "M", "L"}
+
+const (
+	// The urgency added to tasks with the highest priority
+	maxPriorityUrgency = 6
+	// The urgency added to tasks with the lowest priority
+	minPriorityUrgency = 1.8
+)
+
+// Sets the priorities from highest to lowest. Priorities are matched without
+// case, so they must be unique ignoring case.
+func SetPriorities(levels []string) error {
+	if len(levels) == 0 {
+		return errors.New("Invalid priorities, expected at least one priority")
+	}
+
+	for i, level := range levels {
+		if level == "" {
+			return errors.New("Invalid priorities, priorities can't be empty")
+		}
+
+		for _, other := range levels[:i] {
+			if strings.EqualFold(level, other) {
+				return fmt.Errorf("Invalid priorities, \"%s\" is used more than once", level)
+			}
+		}
+	}
+
+	priorities = slices.Clone(levels)
+	return nil
+}
+
+// Returns the priorities from highest to lowest.
+func Priorities() []string {
+	return slices.Clone(priorities)
+}
+
+// Returns the configured priority matching the value without case, such as `H`
+// for `h`. An empty value is returned as is since tasks don't require a
+// priority.
+func ParsePriority(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	for _, priority := range priorities {
+		if strings.EqualFold(priority, value) {
+			return priority, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid priority \"%s\", expected one of %s", value, strings.Join(priorities, ", "))
+}
+
+// Returns the position of the priority from the highest priority, or -1 if it
+// is not a configured priority. Priorities are matched without case.
+func PriorityRank(priority string) int {
+	return slices.IndexFunc(priorities, func(p string) bool {
+		return strings.EqualFold(p, priority)
+	})
+}
+
+// Returns the priorities higher than the priority, or lower than it if above is
+// false.
+func PrioritiesBeyond(priority string, above bool) ([]string, error) {
+	priority, err := ParsePriority(priority)
+	if err != nil {
+		return nil, err
+	}
+
+	if priority == "" {
+		return nil, errors.New("Missing priority")
+	}
+
+	rank := PriorityRank(priority)
+	if above {
+		return Priorities()[:rank], nil
+	}
+
+	return Priorities()[rank+1:], nil
+}
+
+// Returns the urgency added to tasks with the priority. Urgency is spread
+// evenly from the highest to the lowest priority.
+func priorityUrgency(priority string) float64 {
+	rank := PriorityRank(priority)
+	if rank < 0 {
+		return 0
+	}
+
+	if len(priorities) == 1 {
+		return maxPriorityUrgency
+	}
+
+	step := (maxPriorityUrgency - minPriorityUrgency) / float64(len(priorities)-1)
+	return maxPriorityUrgency - step*float64(rank)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePriority(t *testing.T) {
+	for value, expected := range map[string]string{"H": "H", "h": "H", "m": "M", "": ""} {
+		priority, err := ParsePriority(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, priority, value)
+	}
+
+	_, err := ParsePriority("High")
+	assert.EqualError(t, err, "Invalid priority \"High\", expected one of H, M, L")
+}
+
+func TestCustomPriorities(t *testing.T) {
+	defer SetPriorities([]string{"H", "M", "L"})
+	assert.NoError(t, SetPriorities([]string{"P0", "P1", "P2", "P3"}))
+
+	priority, err := ParsePriority("p1")
+	assert.NoError(t, err)
+	assert.Equal(t, "P1", priority)
+
+	_, err = ParsePriority("H")
+	assert.Error(t, err)
+
+	above, err := PrioritiesBeyond("p2", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"P0", "P1"}, above)
+
+	below, err := PrioritiesBeyond("P2", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"P3"}, below)
+
+	above, err = PrioritiesBeyond("P0", true)
+	assert.NoError(t, err)
+	assert.Empty(t, above)
+
+	assert.InDelta(t, 6.0, priorityUrgency("P0"), 0.001)
+	assert.InDelta(t, 4.6, priorityUrgency("P1"), 0.001)
+	assert.InDelta(t, 1.8, priorityUrgency("P3"), 0.001)
+	assert.Equal(t, 0.0, priorityUrgency("H"))
+
+	assert.EqualError(t, SetPriorities([]string{"A", "a"}), "Invalid priorities, \"a\" is used more than once")
+	assert.Error(t, SetPriorities([]string{"A", ""}))
+}
+
+func TestPriorityCase(t *testing.T) {
+	assert.Equal(t, 0, PriorityRank("h"))
+	assert.Equal(t, 2, PriorityRank("l"))
+	assert.Equal(t, -1, PriorityRank("High"))
+	assert.InDelta(t, 6.0, priorityUrgency("h"), 0.001)
+
+	test_utils.UseTempDB(t)
+
+	// Tasks saved before priorities were matched without case
+	for _, priority := range []string{"h", "H"} {
+		task := NewTask()
+		task.Title = "Buy milk"
+		task.Priority = priority
+
+		_, err := Add(task)
+		assert.NoError(t, err)
+	}
+
+	tasks, err := ListTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	for _, task := range tasks {
+		assert.Equal(t, "H", task.Priority)
+	}
+}
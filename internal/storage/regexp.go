@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// The SQLite driver with the custom functions used by tsk registered
+const driverName = "sqlite3_tsk"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", matchRegexp, true)
+		},
+	})
+}
+
+// Compiled patterns are cached since the function is called for every row.
+var patterns sync.Map
+
+// Implements the SQLite `REGEXP` operator, where `value REGEXP pattern` calls
+// `regexp(pattern, value)`. Null values never match.
+func matchRegexp(pattern string, value any) (bool, error) {
+	if value == nil {
+		return false, nil
+	}
+
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+
+		re, _ = patterns.LoadOrStore(pattern, compiled)
+	}
+
+	switch value := value.(type) {
+	case []byte:
+		return re.(*regexp.Regexp).Match(value), nil
+	default:
+		return re.(*regexp.Regexp).MatchString(fmt.Sprint(value)), nil
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRegexp(t *testing.T) {
+	match, err := matchRegexp("^Fix.*login$", "Fix the login")
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = matchRegexp("^Fix", []byte("Don't fix"))
+	assert.NoError(t, err)
+	assert.False(t, match)
+
+	match, err = matchRegexp("^4", int64(42))
+	assert.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = matchRegexp(".*", nil)
+	assert.NoError(t, err)
+	assert.False(t, match)
+
+	_, err = matchRegexp("(", "foo")
+	assert.Error(t, err)
+}
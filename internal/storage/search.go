@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+)
+
+var ErrSearchUnavailable = errors.New("Full-text search is not available, rebuild tsk with `-tags sqlite_fts5` to enable it")
+
+// The meta key set once the search index has been built
+const searchIndexKey = "search_index"
+
+// The search index covers the title and attribute values of each task. Titles
+// and attributes are tokenized without case or diacritics, so `cafe` matches
+// `Café`.
+const searchMigration = `
+    CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5 (
+        task_id UNINDEXED,
+        title,
+        attributes,
+        tokenize = 'unicode61 remove_diacritics 2'
+    );
+
+    CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+        INSERT INTO tasks_fts (task_id, title, attributes) VALUES (
+            new.id,
+            new.data ->> '$.title',
+            (SELECT group_concat(value, ' ') FROM json_each(new.data, '$.attributes'))
+        );
+    END;
+
+    CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE ON tasks BEGIN
+        DELETE FROM tasks_fts WHERE task_id = old.id;
+        INSERT INTO tasks_fts (task_id, title, attributes) VALUES (
+            new.id,
+            new.data ->> '$.title',
+            (SELECT group_concat(value, ' ') FROM json_each(new.data, '$.attributes'))
+        );
+    END;
+
+    CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+        DELETE FROM tasks_fts WHERE task_id = old.id;
+    END;
+`
+
+// Rebuilds the search index from the tasks table
+const searchRebuild = `
+    DELETE FROM tasks_fts;
+
+    INSERT INTO tasks_fts (task_id, title, attributes)
+    SELECT
+        id,
+        data ->> '$.title',
+        (SELECT group_concat(value, ' ') FROM json_each(tasks.data, '$.attributes'))
+    FROM tasks;
+
+    INSERT OR REPLACE INTO meta (key, value) VALUES ('search_index', '1');
+`
+
+// The triggers that keep the index up to date fail without FTS5, so they are
+// removed and the index is rebuilt once FTS5 is available again.
+const searchDisable = `
+    DROP TRIGGER IF EXISTS tasks_fts_insert;
+    DROP TRIGGER IF EXISTS tasks_fts_update;
+    DROP TRIGGER IF EXISTS tasks_fts_delete;
+    DELETE FROM meta WHERE key = 'search_index';
+`
+
+// Returns true if SQLite was built with FTS5, which requires building tsk with
+// the `sqlite_fts5` build tag.
+func searchAvailable(q querier) bool {
+	var enabled bool
+	err := q.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled)
+	return err == nil && enabled
+}
+
+// Creates the search index and the triggers that keep it up to date when
+// tasks are added, edited, or deleted.
+func migrateSearch(q querier) error {
+	if !searchAvailable(q) {
+		_, err := q.Exec(searchDisable)
+		return err
+	}
+
+	if _, err := q.Exec(searchMigration); err != nil {
+		return err
+	}
+
+	var built string
+	err := q.QueryRow("SELECT value FROM meta WHERE key = ?", searchIndexKey).Scan(&built)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = q.Exec(searchRebuild)
+	}
+
+	return err
+}
+
+type SearchResult struct {
+	Task Task
+	// The title of the task with the matched terms wrapped in the markers
+	// passed to `Search`.
+	Title string
+}
+
+// Searches the title and attributes of the tasks matching the filters, most
+// relevant first. The query supports the FTS5 syntax for phrases (`"fix
+// login"`), prefixes (`deploy*`), and boolean operators (`AND`, `OR`, `NOT`).
+// Matched terms in the title are wrapped in the start and end markers.
+func Search(query string, filters []sql_builder.Filter, start string, end string) ([]SearchResult, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to search tasks: %w", err)
+	}
+
+	if !searchAvailable(conn) {
+		return nil, ErrSearchUnavailable
+	}
+
+	builder := sql_builder.New().
+		Select("tasks.id, tasks.template_id, assignments.id, tasks.data, highlight(tasks_fts, 1, ?, ?)").
+		From("tasks_fts").
+		Join("tasks", "tasks.id = tasks_fts.task_id").
+		Join("assignments", "tasks.id = assignments.task_id").
+		Filter(sql_builder.Filter{Key: "tasks_fts", Operator: sql_builder.Match, Value: "?"}).
+		Filter(notDeletedFilter)
+
+	for _, filter := range filters {
+		builder.Filter(filter)
+	}
+
+	builder.OrderBy("rank")
+
+	if os.Getenv("DEBUG") != "" {
+		log.Println(builder.SQL())
+	}
+
+	rows, err := conn.Query(builder.SQL(), start, end, query)
+	if err != nil {
+		// Syntax errors in the query are reported by FTS5 with an `fts5:` prefix
+		if strings.Contains(err.Error(), "fts5:") {
+			return nil, fmt.Errorf("Invalid search query: %w", err)
+		}
+
+		return nil, fmt.Errorf("Failed to search tasks: %w", err)
+	}
+
+	defer rows.Close()
+	results := []SearchResult{}
+
+	for rows.Next() {
+		var result SearchResult
+		var err error
+
+		result.Task, err = scanTask(rows, &result.Title)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to search tasks: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("Invalid search query: %w", err)
+	}
+
+	return results, nil
+}
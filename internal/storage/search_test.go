@@ -0,0 +1,86 @@
+//go:build sqlite_fts5
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func addSearchTask(t *testing.T, title string, attributes map[string]string) Task {
+	task := NewTask()
+	task.Title = title
+	task.Attributes = attributes
+
+	_, err := Add(task)
+	assert.NoError(t, err)
+
+	return task
+}
+
+func searchTitles(t *testing.T, query string) []string {
+	results, err := Search(query, nil, "[", "]")
+	assert.NoError(t, err)
+
+	titles := []string{}
+	for _, result := range results {
+		titles = append(titles, result.Title)
+	}
+
+	return titles
+}
+
+func TestSearch(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	addSearchTask(t, "Meet at the Café", nil)
+	addSearchTask(t, "Review PR", map[string]string{"url": "https://example.com/login"})
+	addSearchTask(t, "Buy milk", nil)
+
+	assert.Equal(t, []string{"Meet at the [Café]"}, searchTitles(t, "cafe"))
+	assert.Equal(t, []string{"Review PR"}, searchTitles(t, "login"))
+	assert.Equal(t, []string{"[Buy] milk"}, searchTitles(t, "bu*"))
+	assert.Empty(t, searchTitles(t, "eggs"))
+
+	_, err := Search("\"unclosed", nil, "", "")
+	assert.ErrorContains(t, err, "Invalid search query")
+}
+
+func TestSearchExcludesDeletedTasks(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	task := addSearchTask(t, "Buy milk", nil)
+	addSearchTask(t, "Buy eggs", nil)
+
+	_, err := Delete([]sql_builder.Filter{TaskIdsFilter([]Task{task})})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"[Buy] eggs"}, searchTitles(t, "buy"))
+}
+
+func TestSearchRebuildsIndex(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	addSearchTask(t, "Buy milk", nil)
+
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	// Tasks edited while search is disabled aren't updated by the triggers,
+	// so the index is rebuilt once search is available again.
+	_, err = conn.Exec(searchDisable)
+	assert.NoError(t, err)
+
+	_, err = conn.Exec("UPDATE tasks SET data = json_set(data, '$.title', 'Buy eggs')")
+	assert.NoError(t, err)
+
+	var stale string
+	assert.NoError(t, conn.QueryRow("SELECT title FROM tasks_fts").Scan(&stale))
+	assert.Equal(t, "Buy milk", stale)
+
+	assert.Equal(t, []string{"Buy [eggs]"}, searchTitles(t, "eggs"))
+	assert.Empty(t, searchTitles(t, "milk"))
+}
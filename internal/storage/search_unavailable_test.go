@@ -0,0 +1,17 @@
+//go:build !sqlite_fts5
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchUnavailable(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	_, err := Search("milk", nil, "", "")
+	assert.ErrorIs(t, err, ErrSearchUnavailable)
+}
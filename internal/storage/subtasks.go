@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+)
+
+// The number of completed items out of the total, such as the subtasks of a
+// task.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// Returns a filter matching the subtasks of the given tasks.
+func parentFilter(tasks []Task) sql_builder.Filter {
+	ids := TaskIdsFilter(tasks)
+
+	return sql_builder.Filter{
+		Key:      "tasks.data ->> '$.parent'",
+		Operator: ids.Operator,
+		Value:    ids.Value,
+	}
+}
+
+// Counts the completed and total subtasks of each task with subtasks, keyed by
+// the unique id of the parent task.
+func SubtaskProgress() (map[string]Progress, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to count subtasks: %w", err)
+	}
+
+	rows, err := conn.Query(`
+		select
+			tasks.data ->> '$.parent',
+			sum(tasks.data ->> '$.status' = 'done'),
+			count(*)
+		from tasks
+		where tasks.data ->> '$.parent' is not null and tasks.data ->> '$.deleted_at' is null
+		group by tasks.data ->> '$.parent'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to count subtasks: %w", err)
+	}
+
+	defer rows.Close()
+	progress := make(map[string]Progress)
+
+	for rows.Next() {
+		var parent string
+		var p Progress
+
+		if err := rows.Scan(&parent, &p.Done, &p.Total); err != nil {
+			return nil, fmt.Errorf("Failed to count subtasks: %w", err)
+		}
+
+		progress[parent] = p
+	}
+
+	return progress, nil
+}
+
+// Gets the open subtasks of the tasks matching the filters, including the
+// subtasks of subtasks. Tasks matching the filters are not included.
+func OpenSubtasks(filters []sql_builder.Filter) ([]Task, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list subtasks: %w", err)
+	}
+
+	parents, err := queryTasks(conn, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list subtasks: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, task := range parents {
+		seen[task.Id] = true
+	}
+
+	subtasks := []Task{}
+	for len(parents) > 0 {
+		children, err := queryTasks(conn, []sql_builder.Filter{openFilter, parentFilter(parents)})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to list subtasks: %w", err)
+		}
+
+		parents = nil
+		for _, child := range children {
+			if !seen[child.Id] {
+				seen[child.Id] = true
+				subtasks = append(subtasks, child)
+				parents = append(parents, child)
+			}
+		}
+	}
+
+	return subtasks, nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Adds a task with the title and parent, returning the added task.
+func addSubtask(t *testing.T, title string, parent *Task, status TaskStatus) Task {
+	task := NewTask()
+	task.Title = title
+	task.Status = status
+
+	if parent != nil {
+		task.Parent = parent.Id
+	}
+
+	_, err := Add(task)
+	assert.NoError(t, err)
+
+	return task
+}
+
+func titles(tasks []Task) []string {
+	result := []string{}
+	for _, task := range tasks {
+		result = append(result, task.Title)
+	}
+
+	return result
+}
+
+func TestOpenSubtasks(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	project := addSubtask(t, "Project", nil, TaskStatusPending)
+	design := addSubtask(t, "Design", &project, TaskStatusPending)
+	addSubtask(t, "Mockups", &design, TaskStatusActive)
+	addSubtask(t, "Research", &design, TaskStatusDone)
+	build := addSubtask(t, "Build", &project, TaskStatusDone)
+	addSubtask(t, "Deploy", &build, TaskStatusPending)
+	addSubtask(t, "Unrelated", nil, TaskStatusPending)
+
+	tests := []struct {
+		name     string
+		matched  []Task
+		expected []string
+	}{
+		{"nested", []Task{project}, []string{"Design", "Mockups"}},
+		{"parent and child", []Task{project, design}, []string{"Mockups"}},
+		{"leaf", []Task{design}, []string{"Mockups"}},
+		{"done parent", []Task{build}, []string{"Deploy"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subtasks, err := OpenSubtasks([]sql_builder.Filter{TaskIdsFilter(test.matched)})
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, test.expected, titles(subtasks))
+		})
+	}
+
+	progress, err := SubtaskProgress()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Progress{
+		project.Id: {Done: 1, Total: 2},
+		design.Id:  {Done: 1, Total: 2},
+		build.Id:   {Done: 0, Total: 1},
+	}, progress)
+}
+
+func TestOpenSubtasksCycle(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	a := NewTask()
+	a.Title = "A"
+	b := NewTask()
+	b.Title = "B"
+	a.Parent = b.Id
+	b.Parent = a.Id
+
+	for _, task := range []Task{a, b} {
+		_, err := Add(task)
+		assert.NoError(t, err)
+	}
+
+	subtasks, err := OpenSubtasks([]sql_builder.Filter{TaskIdsFilter([]Task{a})})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B"}, titles(subtasks))
+}
@@ -0,0 +1,501 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// A change to a single field of a task. Every change made to a task is
+// recorded so that it can be synced to other replicas, and conflicting changes
+// to the same field are resolved by keeping the change with the latest
+// timestamp, using the replica id to break ties.
+type Change struct {
+	TaskId string `json:"task_id"`
+	// The top-level field of the task data that was changed. Deleting a task
+	// is recorded as a change to the special `deleted` field.
+	Field string `json:"field"`
+	// The JSON encoded value of the field, or `null` if the field was removed
+	Value json.RawMessage `json:"value"`
+	// The time of the change in nanoseconds since the Unix epoch
+	Timestamp int64 `json:"timestamp"`
+	// The replica that made the change
+	Replica string `json:"replica"`
+}
+
+// The field used to record that a task was deleted
+const deletedField = "deleted"
+
+type SyncResult struct {
+	Imported int
+	Exported int
+	Replicas int
+}
+
+// Returns true if change a should win over change b.
+func newer(a Change, b Change) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp > b.Timestamp
+	}
+
+	return a.Replica > b.Replica
+}
+
+// Returns the unique id of this replica, creating it if it doesn't exist.
+func replicaId(q querier) (string, error) {
+	var id string
+
+	err := q.QueryRow("SELECT value FROM meta WHERE key = 'replica_id'").Scan(&id)
+	if err == sql.ErrNoRows {
+		id = utils.GenerateId()
+		_, err = q.Exec("INSERT INTO meta (key, value) VALUES ('replica_id', ?)", id)
+	}
+
+	return id, err
+}
+
+// Splits a task into its top-level fields, excluding the ids which are not
+// synced.
+func taskFields(task *Task) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if task == nil {
+		return fields, nil
+	}
+
+	data, err := marshal(*task)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "id")
+	delete(fields, "short_id")
+	return fields, nil
+}
+
+// Returns the fields that changed between two versions of a task. Fields that
+// were removed have a `null` value.
+func diffFields(before *Task, after *Task) (map[string]json.RawMessage, error) {
+	previous, err := taskFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := taskFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]json.RawMessage)
+	for field, value := range next {
+		if !bytes.Equal(previous[field], value) {
+			diff[field] = value
+		}
+	}
+
+	for field := range previous {
+		if _, ok := next[field]; !ok {
+			diff[field] = json.RawMessage("null")
+		}
+	}
+
+	return diff, nil
+}
+
+func insertChange(q querier, change Change) error {
+	_, err := q.Exec(
+		"INSERT INTO changes (task_id, field, value, timestamp, replica) VALUES (?, ?, ?, ?, ?)",
+		change.TaskId,
+		change.Field,
+		string(change.Value),
+		change.Timestamp,
+		change.Replica,
+	)
+
+	return err
+}
+
+// Records the changes between two versions of a task so they can be synced.
+// Pass a nil `before` for new tasks and a nil `after` for deleted tasks.
+func recordChanges(q querier, before *Task, after *Task) error {
+	replica, err := replicaId(q)
+	if err != nil {
+		return fmt.Errorf("Failed to record changes: %w", err)
+	}
+
+	// Local changes always have a later timestamp than any previous change,
+	// even if the system clock moves backwards. The timestamp index keeps
+	// this lookup fast as the changes table grows.
+	var latest int64
+	err = q.QueryRow("SELECT coalesce(max(timestamp), 0) FROM changes").Scan(&latest)
+	if err != nil {
+		return fmt.Errorf("Failed to record changes: %w", err)
+	}
+
+	timestamp := max(time.Now().UnixNano(), latest+1)
+
+	if after == nil {
+		return insertChange(q, Change{
+			TaskId:    before.Id,
+			Field:     deletedField,
+			Value:     json.RawMessage("true"),
+			Timestamp: timestamp,
+			Replica:   replica,
+		})
+	}
+
+	diff, err := diffFields(before, after)
+	if err != nil {
+		return fmt.Errorf("Failed to record changes: %w", err)
+	}
+
+	for field, value := range diff {
+		err := insertChange(q, Change{
+			TaskId:    after.Id,
+			Field:     field,
+			Value:     value,
+			Timestamp: timestamp,
+			Replica:   replica,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to record changes: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Records all fields of tasks that have no recorded changes, such as tasks
+// created before syncing was first used.
+func recordUntracked(q querier) error {
+	tasks, err := selectTasks(q, []sql_builder.Filter{{
+		Operator: sql_builder.NotExists,
+		Value:    "(select 1 from changes where changes.task_id = tasks.id)",
+	}})
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err := recordChanges(q, nil, &task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Applies a change from another replica if it is newer than the latest change
+// to the same field. Deleted tasks are never restored by later changes so
+// that deletes are consistent on every replica.
+func applyChange(q querier, change Change) (bool, error) {
+	var deleted int
+	err := q.QueryRow(
+		"SELECT count(*) FROM changes WHERE task_id = ? AND field = ?",
+		change.TaskId,
+		deletedField,
+	).Scan(&deleted)
+	if err != nil || deleted > 0 {
+		return false, err
+	}
+
+	latest := Change{}
+	err = q.QueryRow(
+		"SELECT timestamp, replica FROM changes WHERE task_id = ? AND field = ? ORDER BY timestamp DESC, replica DESC LIMIT 1",
+		change.TaskId,
+		change.Field,
+	).Scan(&latest.Timestamp, &latest.Replica)
+	if err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+
+	if err == nil && !newer(change, latest) {
+		return false, nil
+	}
+
+	if change.Field == deletedField {
+		if _, err := q.Exec("DELETE FROM tasks WHERE id = ?", change.TaskId); err != nil {
+			return false, err
+		}
+
+		if _, err := q.Exec("DELETE FROM archive WHERE id = ?", change.TaskId); err != nil {
+			return false, err
+		}
+
+		if _, err := q.Exec("DELETE FROM assignments WHERE task_id = ?", change.TaskId); err != nil {
+			return false, err
+		}
+
+		return true, insertChange(q, change)
+	}
+
+	// Changes to tasks archived on this replica are applied to the archive
+	var archived int
+	err = q.QueryRow("SELECT count(*) FROM archive WHERE id = ?", change.TaskId).Scan(&archived)
+	if err != nil {
+		return false, err
+	}
+
+	if archived > 0 {
+		return true, updateField(q, "archive", change)
+	}
+
+	// Create tasks that were added on other replicas. Short ids are assigned
+	// locally, so they never collide with the short ids of other replicas.
+	res, err := q.Exec(
+		"INSERT INTO tasks (id, template_id, data) VALUES (?, '', '{}') ON CONFLICT (id) DO NOTHING",
+		change.TaskId,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if inserted, _ := res.RowsAffected(); inserted > 0 {
+		_, err = q.Exec(
+			"INSERT INTO assignments VALUES ((select max(id) + 1 from assignments), ?)",
+			change.TaskId,
+		)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, updateField(q, "tasks", change)
+}
+
+// Sets the changed field in the data of the task in the table and records the
+// change.
+func updateField(q querier, table string, change Change) error {
+	var err error
+	path := fmt.Sprintf("$.%s", change.Field)
+
+	if string(change.Value) == "null" {
+		_, err = q.Exec(
+			fmt.Sprintf("UPDATE %s SET data = json_remove(data, ?) WHERE id = ?", table),
+			path,
+			change.TaskId,
+		)
+	} else {
+		_, err = q.Exec(
+			fmt.Sprintf("UPDATE %s SET data = json_set(data, ?, json(?)) WHERE id = ?", table),
+			path,
+			string(change.Value),
+			change.TaskId,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	return insertChange(q, change)
+}
+
+func syncPosition(q querier, replica string) (int64, error) {
+	var position int64
+
+	err := q.QueryRow("SELECT position FROM sync_state WHERE replica = ?", replica).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+
+	return position, err
+}
+
+func setSyncPosition(q querier, replica string, position int64) error {
+	_, err := q.Exec(
+		"INSERT INTO sync_state (replica, position) VALUES (?, ?) ON CONFLICT (replica) DO UPDATE SET position = excluded.position",
+		replica,
+		position,
+	)
+
+	return err
+}
+
+// Imports the changes from another replica's change log, starting after the
+// last line that was previously imported. Returns the number of applied
+// changes.
+func importLog(q querier, path string, replica string) (int, error) {
+	position, err := syncPosition(q, replica)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	imported := 0
+	line := int64(0)
+
+	for {
+		text, err := reader.ReadString('\n')
+
+		// Ignore incomplete lines which may still be being written or synced
+		// to this machine.
+		if err != nil {
+			break
+		}
+
+		line++
+		if line <= position {
+			continue
+		}
+
+		var change Change
+		if err := json.Unmarshal([]byte(text), &change); err != nil {
+			return 0, fmt.Errorf("Invalid change on line %d of %s: %w", line, path, err)
+		}
+
+		applied, err := applyChange(q, change)
+		if err != nil {
+			return 0, err
+		}
+
+		if applied {
+			imported++
+		}
+	}
+
+	return imported, setSyncPosition(q, replica, line)
+}
+
+// Appends the local changes that have not yet been exported to this replica's
+// change log. Unlike the position of imported logs, the sync position of this
+// replica is the sequence number of the last exported change.
+func exportLog(q querier, path string, replica string) (int, error) {
+	position, err := syncPosition(q, replica)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := q.Query(
+		"SELECT seq, task_id, field, value, timestamp, replica FROM changes WHERE replica = ? AND seq > ? ORDER BY seq",
+		replica,
+		position,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	exported := 0
+
+	for rows.Next() {
+		var change Change
+		var value string
+
+		err := rows.Scan(&position, &change.TaskId, &change.Field, &value, &change.Timestamp, &change.Replica)
+		if err != nil {
+			return 0, err
+		}
+
+		change.Value = json.RawMessage(value)
+		if err := encoder.Encode(change); err != nil {
+			return 0, err
+		}
+
+		exported++
+	}
+
+	if exported == 0 {
+		return 0, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return exported, setSyncPosition(q, replica, position)
+}
+
+// Syncs the tasks with other replicas through a shared directory. Each replica
+// writes its changes to an append-only log named after the replica id, and
+// imports the changes from the logs of every other replica.
+func Sync(dir string) (SyncResult, error) {
+	result := SyncResult{}
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return result, fmt.Errorf("Failed to sync: %s is not a directory", dir)
+	}
+
+	conn, err := connect()
+	if err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	replica, err := replicaId(tx)
+	if err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	if err := recordUntracked(tx); err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	logs, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	sort.Strings(logs)
+
+	for _, path := range logs {
+		other := strings.TrimSuffix(filepath.Base(path), ".jsonl")
+		if other == replica {
+			continue
+		}
+
+		imported, err := importLog(tx, path, other)
+		if err != nil {
+			return result, fmt.Errorf("Failed to sync: %w", err)
+		}
+
+		result.Imported += imported
+		result.Replicas++
+	}
+
+	result.Exported, err = exportLog(tx, filepath.Join(dir, replica+".jsonl"), replica)
+	if err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("Failed to sync: %w", err)
+	}
+
+	return result, nil
+}
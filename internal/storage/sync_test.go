@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewer(t *testing.T) {
+	a := Change{Timestamp: 2, Replica: "a"}
+	b := Change{Timestamp: 1, Replica: "b"}
+
+	assert.True(t, newer(a, b))
+	assert.False(t, newer(b, a))
+
+	// Ties are broken by the replica id
+	b.Timestamp = 2
+	assert.True(t, newer(b, a))
+	assert.False(t, newer(a, b))
+}
+
+func TestDiffFields(t *testing.T) {
+	before := Task{Id: "abc", ShortId: 1, Title: "Foo", Tags: []string{"home"}}
+	after := before
+	after.ShortId = 2
+	after.Title = "Bar"
+	after.Tags = []string{"home"}
+
+	diff, err := diffFields(&before, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"title": json.RawMessage(`"Bar"`)}, diff)
+
+	diff, err = diffFields(nil, &after)
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "title")
+	assert.NotContains(t, diff, "id")
+	assert.NotContains(t, diff, "short_id")
+}
+
+func TestRecordUntracked(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	for _, title := range []string{"Tracked", "Untracked"} {
+		task := NewTask()
+		task.Title = title
+		_, err := Add(task)
+		assert.NoError(t, err)
+	}
+
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	tasks, err := queryTasks(conn, nil)
+	assert.NoError(t, err)
+
+	_, err = conn.Exec("DELETE FROM changes WHERE task_id = ?", tasks[1].Id)
+	assert.NoError(t, err)
+
+	var before int
+	assert.NoError(t, conn.QueryRow("SELECT count(*) FROM changes").Scan(&before))
+
+	assert.NoError(t, recordUntracked(conn))
+
+	var tracked, untracked int
+	assert.NoError(t, conn.QueryRow("SELECT count(*) FROM changes WHERE task_id = ?", tasks[0].Id).Scan(&tracked))
+	assert.NoError(t, conn.QueryRow("SELECT count(*) FROM changes WHERE task_id = ?", tasks[1].Id).Scan(&untracked))
+	assert.Equal(t, before, tracked)
+	assert.Equal(t, tracked, untracked)
+}
+
+func TestLatestChangeUsesIndex(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	var id, parent, unused int
+	var detail string
+	err = conn.QueryRow("EXPLAIN QUERY PLAN SELECT coalesce(max(timestamp), 0) FROM changes").Scan(&id, &parent, &unused, &detail)
+	assert.NoError(t, err)
+	assert.Contains(t, detail, "changes_timestamp")
+}
+
+// Creates the databases of two replicas syncing through a shared directory,
+// returning a function that switches to the database of a replica. The
+// replicas are given fixed ids so ties are broken predictably.
+func useReplicas(t *testing.T) (func(replica string), string) {
+	test_utils.UseTempDB(t)
+	dir := t.TempDir()
+
+	shared := filepath.Join(dir, "shared")
+	assert.NoError(t, os.Mkdir(shared, 0o755))
+
+	use := func(replica string) {
+		t.Setenv("DATABASE_URL", filepath.Join(dir, replica+".db"))
+	}
+
+	for _, replica := range []string{"a", "b"} {
+		use(replica)
+
+		conn, err := connect()
+		assert.NoError(t, err)
+
+		_, err = conn.Exec("INSERT INTO meta (key, value) VALUES ('replica_id', ?)", replica)
+		assert.NoError(t, err)
+	}
+
+	return use, shared
+}
+
+// Syncs the replicas in order, switching to the database of each.
+func syncReplicas(t *testing.T, use func(replica string), shared string, replicas ...string) {
+	for _, replica := range replicas {
+		use(replica)
+
+		_, err := Sync(shared)
+		assert.NoError(t, err)
+	}
+}
+
+func TestSyncAddedTasks(t *testing.T) {
+	use, shared := useReplicas(t)
+
+	use("a")
+	addTask(t, "Buy milk")
+	addTask(t, "Buy eggs")
+
+	use("b")
+	addTask(t, "Walk dog")
+
+	syncReplicas(t, use, shared, "a", "b", "a")
+
+	// Short ids are assigned locally, so tasks from the other replica get the
+	// next available ids rather than colliding with local tasks
+	use("a")
+	assert.Equal(t, map[int]string{1: "Buy milk", 2: "Buy eggs", 3: "Walk dog"}, titlesByShortId(t))
+
+	use("b")
+	assert.Equal(t, map[int]string{1: "Walk dog", 2: "Buy milk", 3: "Buy eggs"}, titlesByShortId(t))
+}
+
+func TestSyncConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		// Sets the timestamps of both title changes to the same value
+		tie      bool
+		expected string
+	}{
+		{name: "latest change wins", expected: "Buy oat milk"},
+		{name: "replica breaks ties", tie: true, expected: "Buy milk and eggs"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			use, shared := useReplicas(t)
+
+			use("a")
+			task := addTask(t, "Buy milk")
+			syncReplicas(t, use, shared, "a", "b")
+
+			// The edit on `b` is made first, so the edit on `a` is newer
+			edits := map[string]string{"b": "Buy milk and eggs", "a": "Buy oat milk"}
+			for _, replica := range []string{"b", "a"} {
+				use(replica)
+
+				edited := task
+				edited.Title = edits[replica]
+				assert.NoError(t, Update(edited))
+
+				if test.tie {
+					conn, err := connect()
+					assert.NoError(t, err)
+
+					_, err = conn.Exec("UPDATE changes SET timestamp = 1e18 WHERE field = 'title' AND replica = ?", replica)
+					assert.NoError(t, err)
+				}
+			}
+
+			syncReplicas(t, use, shared, "a", "b", "a")
+
+			for _, replica := range []string{"a", "b"} {
+				use(replica)
+				assert.Equal(t, map[int]string{1: test.expected}, titlesByShortId(t), replica)
+			}
+		})
+	}
+}
+
+func TestSyncPurgeWinsOverLaterEdits(t *testing.T) {
+	use, shared := useReplicas(t)
+
+	use("a")
+	task := addTask(t, "Buy milk")
+	syncReplicas(t, use, shared, "a", "b")
+
+	use("a")
+	filters := []sql_builder.Filter{TaskIdsFilter([]Task{task})}
+	_, err := Delete(filters)
+	assert.NoError(t, err)
+	_, err = Purge(filters, time.Time{})
+	assert.NoError(t, err)
+
+	use("b")
+	task.Title = "Buy oat milk"
+	assert.NoError(t, Update(task))
+
+	syncReplicas(t, use, shared, "a", "b", "a")
+
+	for _, replica := range []string{"a", "b"} {
+		use(replica)
+		assert.Empty(t, titlesByShortId(t), replica)
+
+		trash, err := ListTrash(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, trash, replica)
+	}
+}
+
+func TestSyncArchivedTasks(t *testing.T) {
+	use, shared := useReplicas(t)
+
+	use("a")
+	task := addTask(t, "Buy milk")
+	task.Status = TaskStatusDone
+	assert.NoError(t, Update(task))
+	syncReplicas(t, use, shared, "a", "b")
+
+	use("a")
+	_, err := Archive(nil, time.Time{})
+	assert.NoError(t, err)
+
+	use("b")
+	task.Title = "Buy oat milk"
+	assert.NoError(t, Update(task))
+
+	syncReplicas(t, use, shared, "b", "a")
+
+	// The archived task is updated without moving it back to the task list
+	use("a")
+	assert.Empty(t, titlesByShortId(t))
+
+	archived, err := GetArchivedTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, archived, 1)
+	assert.Equal(t, "Buy oat milk", archived[0].Title)
+}
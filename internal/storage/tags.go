@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+)
+
+type TagCount struct {
+	Tag string `json:"tag"`
+	// The number of tasks with the tag that are not done
+	Pending int `json:"pending"`
+	// The number of completed tasks with the tag
+	Done int `json:"done"`
+}
+
+// Counts the open and completed tasks using each tag, sorted by tag.
+func CountTags() ([]TagCount, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to count tags: %w", err)
+	}
+
+	rows, err := conn.Query(`
+		select
+			tags.value,
+			sum(tasks.data ->> '$.status' != 'done'),
+			sum(tasks.data ->> '$.status' = 'done')
+		from tasks, json_each(tasks.data, '$.tags') as tags
+		where tasks.data ->> '$.deleted_at' is null
+		group by tags.value
+		order by tags.value
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to count tags: %w", err)
+	}
+
+	defer rows.Close()
+	counts := []TagCount{}
+
+	for rows.Next() {
+		var count TagCount
+		if err := rows.Scan(&count.Tag, &count.Pending, &count.Done); err != nil {
+			return nil, fmt.Errorf("Failed to count tags: %w", err)
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, nil
+}
+
+// Returns the tags with the tag replaced, keeping a single copy of the
+// replacement if the tags already include it. An empty replacement removes the
+// tag.
+func ReplaceTagIn(tags []string, tag string, replacement string) []string {
+	result := make([]string, 0, len(tags))
+
+	for _, t := range tags {
+		if t == tag {
+			t = replacement
+		}
+
+		if t != "" && !slices.Contains(result, t) {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// Replaces a tag in all tasks matching the filters, or removes the tag if the
+// replacement is empty. The tasks are updated in a single transaction, so if
+// any hook rejects the change none of the tasks are updated.
+func ReplaceTag(filters []sql_builder.Filter, tag string, replacement string) ([]int, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update tags: %w", err)
+	}
+
+	tasks, err := queryTasks(conn, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update tags: %w", err)
+	}
+
+	var before, after []Task
+	for _, task := range tasks {
+		if !slices.Contains(task.Tags, tag) {
+			continue
+		}
+
+		updated := task
+		updated.Tags = ReplaceTagIn(task.Tags, tag, replacement)
+
+		updated, err := prepare(task, updated)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to update tags: %w", err)
+		}
+
+		before = append(before, task)
+		after = append(after, updated)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to update tags: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	ids := []int{}
+	for i, task := range after {
+		if err := save(tx, before[i], task); err != nil {
+			return nil, fmt.Errorf("Failed to update tags: %w", err)
+		}
+
+		ids = append(ids, task.ShortId)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to update tags: %w", err)
+	}
+
+	return ids, nil
+}
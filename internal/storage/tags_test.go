@@ -0,0 +1,14 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceTagIn(t *testing.T) {
+	assert.Equal(t, []string{"a", "shopping", "b"}, ReplaceTagIn([]string{"a", "shoping", "b"}, "shoping", "shopping"))
+	assert.Equal(t, []string{"home", "work"}, ReplaceTagIn([]string{"home", "house", "work"}, "house", "home"))
+	assert.Equal(t, []string{"a", "b"}, ReplaceTagIn([]string{"a", "x", "b"}, "x", ""))
+	assert.Equal(t, []string{"a"}, ReplaceTagIn([]string{"a"}, "x", "y"))
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mskelton/tsk/internal/hooks"
 	"github.com/mskelton/tsk/internal/sql_builder"
 	"github.com/mskelton/tsk/internal/utils"
 )
@@ -22,13 +26,13 @@ const (
 
 type Task struct {
 	// The unique identifier for the task
-	Id string
+	Id string `json:"id"`
 	// A short numerical identifier for the task, used for quick reference in the UI.
-	ShortId int
+	ShortId int `json:"short_id,omitempty"`
 	// The parent recurrence template the task was created from (if any). This
 	// is used when finding other tasks from the same recurrence template or
 	// when modifying the recurrence options.
-	TemplateId string
+	TemplateId string `json:"template_id,omitempty"`
 	// The title of the task
 	Title string `json:"title"`
 	// The priority of the task, typically something like `H`, `M`, or `L`,
@@ -42,6 +46,28 @@ type Task struct {
 	// A list of tags for the task. Tags are useful for grouping tasks together
 	// and can be used to filter tasks in the UI.
 	Tags []string `json:"tags"`
+	// The project the task belongs to (if any)
+	Project string `json:"project,omitempty"`
+	// The unique id of the parent task if this is a subtask
+	Parent string `json:"parent,omitempty"`
+	// An ordered list of steps for tasks that don't need subtasks
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	// Extra key/value attributes of the task, such as those imported from
+	// other formats, that don't have a dedicated field.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// The time the task was completed, or nil if it is not done
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// The date the task is due (if any)
+	Due *time.Time `json:"due,omitempty"`
+	// The date work on the task can start (if any). Tasks are given increased
+	// urgency once the date is reached.
+	Scheduled *time.Time `json:"scheduled,omitempty"`
+	// The date the task is hidden until (if any). Waiting tasks are not
+	// included in the task list until the date is reached.
+	Wait *time.Time `json:"wait,omitempty"`
+	// The time the task was moved to the trash, or nil if it is not deleted.
+	// Deleted tasks keep their short id until they are purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 	// The time the task was created
 	CreatedAt time.Time `json:"created_at"`
 	// The time the task was last updated
@@ -58,21 +84,89 @@ func NewTask() Task {
 	}
 }
 
+// Returns a copy of the task with a new id, a pending status, new timestamps,
+// and an unchecked checklist. The copy is not part of the recurrence template of the task.
+func (t Task) Duplicate() Task {
+	task := NewTask()
+	task.Title = t.Title
+	task.Priority = t.Priority
+	task.Tags = append(task.Tags, t.Tags...)
+	task.Project = t.Project
+	task.Parent = t.Parent
+	task.Due = t.Due
+	task.Scheduled = t.Scheduled
+	task.Wait = t.Wait
+
+	if t.Attributes != nil {
+		task.Attributes = maps.Clone(t.Attributes)
+	}
+
+	// The checklist starts over in the copy
+	for _, item := range t.Checklist {
+		task.Checklist = append(task.Checklist, ChecklistItem{Text: item.Text})
+	}
+
+	return task
+}
+
+var openFilter = sql_builder.Filter{
+	Key:      "tasks.data ->> '$.status'",
+	Operator: sql_builder.Neq,
+	Value:    "'done'",
+}
+
+// Returns a filter comparing the wait date of tasks to the current time.
+// Tasks without a wait date are treated as having waited since the epoch.
+func waitFilter(operator sql_builder.Operator) sql_builder.Filter {
+	return sql_builder.Filter{
+		Key:      "coalesce(CAST(strftime('%s', tasks.data ->> '$.wait') AS INTEGER), 0)",
+		Operator: operator,
+		Value:    strconv.FormatInt(time.Now().Unix(), 10),
+	}
+}
+
+// Lists the tasks matching the filters, excluding completed and waiting tasks.
+// Tasks are sorted by their urgency.
 func ListTasks(filters []sql_builder.Filter) ([]Task, error) {
+	defaults := []sql_builder.Filter{openFilter, waitFilter(sql_builder.Lte)}
+
+	tasks, err := GetTasks(append(defaults, filters...))
+	if err != nil {
+		return nil, err
+	}
+
+	sortByUrgency(tasks, time.Now())
+	return tasks, nil
+}
+
+// Lists the tasks matching the filters that are hidden until their wait date,
+// excluding completed tasks.
+func ListWaiting(filters []sql_builder.Filter) ([]Task, error) {
+	defaults := []sql_builder.Filter{openFilter, waitFilter(sql_builder.Gt)}
+	return GetTasks(append(defaults, filters...))
+}
+
+// Gets all tasks matching the filters regardless of their status.
+func GetTasks(filters []sql_builder.Filter) ([]Task, error) {
 	conn, err := connect()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to list tasks: %w", err)
 	}
 
+	return queryTasks(conn, filters)
+}
+
+// Queries the tasks matching the filters, excluding tasks in the trash.
+func queryTasks(q querier, filters []sql_builder.Filter) ([]Task, error) {
+	return selectTasks(q, append([]sql_builder.Filter{notDeletedFilter}, filters...))
+}
+
+// Queries the tasks matching the filters, including tasks in the trash.
+func selectTasks(q querier, filters []sql_builder.Filter) ([]Task, error) {
 	builder := sql_builder.New().
 		Select("tasks.id, tasks.template_id, assignments.id, tasks.data").
 		From("tasks").
-		Join("assignments", "tasks.id = assignments.task_id").
-		Filter(sql_builder.Filter{
-			Key:      "tasks.data ->> '$.status'",
-			Operator: sql_builder.Neq,
-			Value:    "'done'",
-		})
+		Join("assignments", "tasks.id = assignments.task_id")
 
 	for _, filter := range filters {
 		builder.Filter(filter)
@@ -82,48 +176,143 @@ func ListTasks(filters []sql_builder.Filter) ([]Task, error) {
 		log.Println(builder.SQL())
 	}
 
-	rows, err := conn.Query(builder.SQL())
+	rows, err := q.Query(builder.SQL())
 	if err != nil {
 		return nil, fmt.Errorf("Failed to list tasks: %w", err)
 	}
 
+	defer rows.Close()
 	var tasks []Task
 
 	for rows.Next() {
-		var taskId string
-		var templateId sql.NullString
-		var shortId sql.NullInt64
-		var data []byte
-
-		err = rows.Scan(&taskId, &templateId, &shortId, &data)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("Failed to list tasks: %w", err)
 		}
 
-		var task Task
-		err = json.Unmarshal(data, &task)
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// Scans a row selecting the task id, template id, short id, and data, followed
+// by any extra columns which are scanned into the extra destinations.
+func scanTask(rows *sql.Rows, extra ...any) (Task, error) {
+	var task Task
+	var taskId string
+	var templateId sql.NullString
+	var shortId sql.NullInt64
+	var data []byte
+
+	dest := append([]any{&taskId, &templateId, &shortId, &data}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return task, err
+	}
+
+	if err := json.Unmarshal(data, &task); err != nil {
+		return task, err
+	}
+
+	task.Id = taskId
+
+	// Tasks saved before priorities were matched without case can have a
+	// priority such as `h`, which is read as the configured priority `H`.
+	if priority, err := ParsePriority(task.Priority); err == nil {
+		task.Priority = priority
+	}
+
+	if shortId.Valid {
+		task.ShortId = int(shortId.Int64)
+	}
+
+	if templateId.Valid {
+		task.TemplateId = templateId.String
+	}
+
+	return task, nil
+}
+
+// Serializes a task for storage. Short ids are stored in the assignments
+// table rather than the task data.
+func marshal(task Task) ([]byte, error) {
+	task.ShortId = 0
+	return json.Marshal(task)
+}
+
+// Returns a filter matching the given tasks by their unique ids.
+func TaskIdsFilter(tasks []Task) sql_builder.Filter {
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, quote(task.Id))
+	}
+
+	return sql_builder.Filter{
+		Key:      "tasks.id",
+		Operator: sql_builder.In,
+		Value:    fmt.Sprintf("(%s)", strings.Join(ids, ", ")),
+	}
+}
+
+// Quotes a string for use in SQL.
+func quote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
+
+// Sets or clears the completion time of a task when its status changes to or
+// from done.
+func setCompletedAt(before Task, after *Task) {
+	if after.Status == TaskStatusDone && before.Status != TaskStatusDone && after.CompletedAt == nil {
+		now := time.Now()
+		after.CompletedAt = &now
+	} else if after.Status != TaskStatusDone {
+		after.CompletedAt = nil
+	}
+}
+
+// Runs the hooks for an event, returning the task as modified by the hooks.
+// Hooks are not allowed to change the identity of the task.
+func runHooks(event hooks.Event, before *Task, after Task) (Task, error) {
+	var input [][]byte
+
+	if before != nil {
+		data, err := json.Marshal(before)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to list tasks: %w", err)
+			return after, err
 		}
 
-		task.Id = taskId
+		input = append(input, data)
+	}
 
-		if shortId.Valid {
-			task.ShortId = int(shortId.Int64)
-		}
+	data, err := json.Marshal(after)
+	if err != nil {
+		return after, err
+	}
 
-		if templateId.Valid {
-			task.TemplateId = templateId.String
-		}
+	output, err := hooks.Run(event, append(input, data)...)
+	if err != nil {
+		return after, err
+	}
 
-		tasks = append(tasks, task)
+	var modified Task
+	if err := json.Unmarshal(output, &modified); err != nil {
+		return after, fmt.Errorf("Hook %s returned invalid task JSON: %w", event, err)
 	}
 
-	return tasks, nil
+	modified.Id = after.Id
+	modified.ShortId = after.ShortId
+	modified.TemplateId = after.TemplateId
+
+	return modified, nil
 }
 
 func Add(task Task) (int64, error) {
-	data, err := json.Marshal(task)
+	task, err := runHooks(hooks.OnAdd, nil, task)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to add task: %w", err)
+	}
+
+	data, err := marshal(task)
 	if err != nil {
 		return 0, fmt.Errorf("Failed to add task: %w", err)
 	}
@@ -133,7 +322,14 @@ func Add(task Task) (int64, error) {
 		return 0, fmt.Errorf("Failed to add task: %w", err)
 	}
 
-	_, err = conn.Exec(
+	tx, err := conn.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to add task: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
 		"INSERT INTO tasks (id, template_id, data) VALUES (?, ?, ?)",
 		task.Id,
 		task.TemplateId,
@@ -144,7 +340,7 @@ func Add(task Task) (int64, error) {
 	}
 
 	// Add an id assignment for the newly created task
-	res, err := conn.Exec(
+	res, err := tx.Exec(
 		"INSERT INTO assignments VALUES ((select max(id) + 1 from assignments), ?)",
 		task.Id,
 	)
@@ -157,6 +353,14 @@ func Add(task Task) (int64, error) {
 		return 0, fmt.Errorf("Failed to get last insert id: %w", err)
 	}
 
+	if err := recordChanges(tx, nil, &task); err != nil {
+		return 0, fmt.Errorf("Failed to add task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("Failed to add task: %w", err)
+	}
+
 	// Return the id of the newly created task. Thankfully SQLite handles this
 	// automatically with `LastInsertId()` since we are using a numeric id.
 	return id, nil
@@ -171,7 +375,8 @@ func Count(filters []sql_builder.Filter) (int, error) {
 	builder := sql_builder.New().
 		Select("count(tasks.id)").
 		From("tasks").
-		Join("assignments", "tasks.id = assignments.task_id")
+		Join("assignments", "tasks.id = assignments.task_id").
+		Filter(notDeletedFilter)
 
 	for _, filter := range filters {
 		builder.Filter(filter)
@@ -196,98 +401,195 @@ func Count(filters []sql_builder.Filter) (int, error) {
 	return count, nil
 }
 
-func getIds(conn *sql.DB, filters []sql_builder.Filter) ([]int, error) {
-	builder := sql_builder.New().
-		Select("assignments.id").
-		From("tasks").
-		Join("assignments", "tasks.id = assignments.task_id")
+type QueryEdit struct {
+	Path  string
+	Value string
+}
 
-	for _, filter := range filters {
-		builder.Filter(filter)
+// Edits the tasks matching the filters. The `on-modify` hooks are run for each
+// edited task, and the `on-done` hooks are run for tasks that were completed.
+// If any hook rejects the change, none of the tasks are edited.
+func Edit(filters []sql_builder.Filter, edits []QueryEdit) ([]int, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to edit tasks: %w", err)
 	}
 
-	debug := os.Getenv("DEBUG") != ""
-	if debug {
-		log.Println(builder.SQL())
+	before, err := queryTasks(conn, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to edit tasks: %w", err)
 	}
 
-	res, err := conn.Query(builder.SQL())
-	if err != nil {
-		return nil, fmt.Errorf("Failed to get task ids: %w", err)
+	if len(before) == 0 {
+		return []int{}, nil
 	}
 
-	var ids []int
-	for res.Next() {
-		var id int
-		err = res.Scan(&id)
+	// SQLite ignores all but the last assignment to a path, so every edit
+	// must be applied in a single call to `json_set`.
+	var paths []string
+	var params []any
 
+	for _, edit := range edits {
+		paths = append(paths, fmt.Sprintf("'$.%s', ?", edit.Path))
+		params = append(params, edit.Value)
+	}
+
+	// The edits are applied to the data of each task without writing it, so
+	// the hooks can run before the write transaction is opened.
+	query := fmt.Sprintf("SELECT json_set(?, %s)", strings.Join(paths, ", "))
+
+	if os.Getenv("DEBUG") != "" {
+		log.Println(query)
+	}
+
+	var after []Task
+	for _, original := range before {
+		data, err := marshal(original)
 		if err != nil {
-			return nil, fmt.Errorf("Failed to get task id: %w", err)
+			return nil, fmt.Errorf("Failed to edit tasks: %w", err)
 		}
 
-		ids = append(ids, id)
-	}
+		if err := conn.QueryRow(query, append([]any{data}, params...)...).Scan(&data); err != nil {
+			return nil, fmt.Errorf("Failed to edit tasks: %w", err)
+		}
 
-	return ids, nil
-}
+		var edited Task
+		if err := json.Unmarshal(data, &edited); err != nil {
+			return nil, fmt.Errorf("Failed to edit tasks: %w", err)
+		}
 
-type QueryEdit struct {
-	Path  string
-	Value string
-}
+		edited.ShortId = original.ShortId
 
-func Edit(filters []sql_builder.Filter, edits []QueryEdit) ([]int, error) {
-	conn, err := connect()
+		modified, err := prepare(original, edited)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to edit tasks: %w", err)
+		}
+
+		after = append(after, modified)
+	}
+
+	tx, err := conn.Begin()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to edit tasks: %w", err)
 	}
 
-	builder := sql_builder.New().Update("tasks")
-	var params []any
+	defer tx.Rollback()
 
-	for _, edit := range edits {
-		params = append(params, edit.Value)
-		builder.Set(fmt.Sprintf("data = json_set(data, '$.%s', ?)", edit.Path))
+	var shortIds []int
+	for i, task := range after {
+		if err := save(tx, before[i], task); err != nil {
+			return nil, fmt.Errorf("Failed to edit tasks: %w", err)
+		}
+
+		shortIds = append(shortIds, task.ShortId)
 	}
 
-	for _, filter := range filters {
-		builder.Filter(filter)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to edit tasks: %w", err)
 	}
 
-	debug := os.Getenv("DEBUG") != ""
-	if debug {
-		log.Println(builder.SQL())
+	return shortIds, nil
+}
+
+// Sets the `UpdatedAt` and completion times of the new data of a task and
+// runs the `on-modify` hooks, as well as the `on-done` hooks if the task was
+// completed. Hooks must run before the write transaction is opened, since a
+// hook that runs tsk would otherwise find the database locked.
+func prepare(before Task, task Task) (Task, error) {
+	task.UpdatedAt = time.Now()
+	setCompletedAt(before, &task)
+
+	task, err := runHooks(hooks.OnModify, &before, task)
+	if err != nil {
+		return task, err
+	}
+
+	if task.Status == TaskStatusDone && before.Status != TaskStatusDone {
+		return runHooks(hooks.OnDone, &before, task)
 	}
 
-	_, err = conn.Exec(builder.SQL(), params...)
+	return task, nil
+}
+
+// Saves the new data of a task returned by `prepare` and records the changes
+// so they can be synced.
+func save(q querier, before Task, task Task) error {
+	data, err := marshal(task)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to edit tasks: %w", err)
+		return err
 	}
 
-	return getIds(conn, filters)
+	if _, err := q.Exec("UPDATE tasks SET data = ? WHERE id = ?", data, task.Id); err != nil {
+		return err
+	}
+
+	return recordChanges(q, &before, &task)
 }
 
-func Delete(filters []sql_builder.Filter) ([]int, error) {
+// Replaces the data of an existing task. The `on-modify` hooks are run before
+// the task is saved. Tasks in the trash can be updated as well, which restores
+// them if the updated task has no `deleted_at` time.
+func Update(task Task) error {
 	conn, err := connect()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		return fmt.Errorf("Failed to update task: %w", err)
 	}
 
-	builder := sql_builder.New().Delete("tasks")
+	before, err := selectTasks(conn, []sql_builder.Filter{TaskIdsFilter([]Task{task})})
+	if err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
 
-	for _, filter := range filters {
-		builder.Filter(filter)
+	if len(before) == 0 {
+		return fmt.Errorf("Failed to update task: task %s does not exist", task.Id)
 	}
 
-	debug := os.Getenv("DEBUG") != ""
-	if debug {
-		log.Println(builder.SQL())
+	task, err = prepare(before[0], task)
+	if err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	if err := save(tx, before[0], task); err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Failed to update task: %w", err)
+	}
+
+	return nil
+}
+
+// Lists the unique tags used by open tasks, sorted alphabetically.
+func ListTags() ([]string, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list tags: %w", err)
 	}
 
-	_, err = conn.Exec(builder.SQL())
+	rows, err := conn.Query(
+		"select distinct tags.value from tasks, json_each(tasks.data, '$.tags') as tags where tasks.data ->> '$.deleted_at' is null and tasks.data ->> '$.status' != 'done' order by tags.value",
+	)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		return nil, fmt.Errorf("Failed to list tags: %w", err)
+	}
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("Failed to list tags: %w", err)
+		}
+
+		tags = append(tags, tag)
 	}
 
-	return getIds(conn, filters)
+	return tags, nil
 }
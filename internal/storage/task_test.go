@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Adds a task with the title, returning the added task.
+func addTask(t *testing.T, title string) Task {
+	task := NewTask()
+	task.Title = title
+
+	_, err := Add(task)
+	assert.NoError(t, err)
+	return task
+}
+
+// Returns the titles of the tasks in the task list by their short ids.
+func titlesByShortId(t *testing.T) map[int]string {
+	tasks, err := GetTasks(nil)
+	assert.NoError(t, err)
+
+	titles := make(map[int]string)
+	for _, task := range tasks {
+		titles[task.ShortId] = task.Title
+	}
+
+	return titles
+}
+
+func TestDuplicate(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	due := time.Date(2026, 10, 25, 0, 0, 0, 0, time.Local)
+
+	task := Task{
+		Id:          "abc",
+		ShortId:     12,
+		TemplateId:  "template",
+		Title:       "Deploy",
+		Priority:    "H",
+		Status:      TaskStatusDone,
+		Tags:        []string{"work"},
+		Project:     "tsk",
+		Attributes:  map[string]string{"url": "https://example.com"},
+		Checklist:   []ChecklistItem{{Text: "Tag release", Done: true}},
+		CompletedAt: &created,
+		Due:         &due,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	duplicate := task.Duplicate()
+
+	assert.NotEqual(t, task.Id, duplicate.Id)
+	assert.Equal(t, 0, duplicate.ShortId)
+	assert.Equal(t, "", duplicate.TemplateId)
+	assert.Equal(t, TaskStatusPending, duplicate.Status)
+	assert.Nil(t, duplicate.CompletedAt)
+	assert.True(t, duplicate.CreatedAt.After(created))
+	assert.Equal(t, "Deploy", duplicate.Title)
+	assert.Equal(t, "H", duplicate.Priority)
+	assert.Equal(t, "tsk", duplicate.Project)
+	assert.Equal(t, &due, duplicate.Due)
+	assert.Equal(t, []ChecklistItem{{Text: "Tag release"}}, duplicate.Checklist)
+
+	// The tags and attributes are not shared with the original task
+	duplicate.Tags[0] = "home"
+	duplicate.Attributes["url"] = ""
+	assert.Equal(t, []string{"work"}, task.Tags)
+	assert.Equal(t, "https://example.com", task.Attributes["url"])
+}
+
+func TestQuotedIds(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	task := NewTask()
+	task.Id = "it's-1"
+	task.Title = "Buy milk"
+
+	_, err := Add(task)
+	assert.NoError(t, err)
+
+	task.Title = "Buy oat milk"
+	assert.NoError(t, Update(task))
+
+	filters := []sql_builder.Filter{TaskIdsFilter([]Task{task})}
+	tasks, err := GetTasks(filters)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "Buy oat milk", tasks[0].Title)
+
+	_, err = Delete(filters)
+	assert.NoError(t, err)
+
+	ids, err := Purge(nil, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{tasks[0].ShortId}, ids)
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mskelton/tsk/internal/hooks"
+	"github.com/mskelton/tsk/internal/sql_builder"
+)
+
+// Matches tasks that are not in the trash
+var notDeletedFilter = sql_builder.Filter{
+	Key:      "tasks.data ->> '$.deleted_at'",
+	Operator: sql_builder.Is,
+	Value:    "null",
+}
+
+// Matches tasks that are in the trash
+var deletedFilter = sql_builder.Filter{
+	Key:      "tasks.data ->> '$.deleted_at'",
+	Operator: sql_builder.IsNot,
+	Value:    "null",
+}
+
+// Moves the tasks matching the filters to the trash after running the
+// `on-delete` hooks for each task. If any hook rejects the change, none of the
+// tasks are deleted.
+func Delete(filters []sql_builder.Filter) ([]int, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+	}
+
+	tasks, err := queryTasks(conn, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+	}
+
+	// Hooks are run before the write transaction is opened so they can run
+	// tsk without the database being locked.
+	for _, task := range tasks {
+		if _, err := runHooks(hooks.OnDelete, nil, task); err != nil {
+			return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		}
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	now := time.Now()
+	ids := []int{}
+
+	for _, task := range tasks {
+		deleted := task
+		deleted.DeletedAt = &now
+		deleted.UpdatedAt = now
+
+		data, err := marshal(deleted)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		}
+
+		if _, err := tx.Exec("UPDATE tasks SET data = ? WHERE id = ?", data, task.Id); err != nil {
+			return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		}
+
+		if err := recordChanges(tx, &task, &deleted); err != nil {
+			return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+		}
+
+		ids = append(ids, task.ShortId)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to delete tasks: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Lists the tasks in the trash matching the filters, most recently deleted
+// first.
+func ListTrash(filters []sql_builder.Filter) ([]Task, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list deleted tasks: %w", err)
+	}
+
+	tasks, err := selectTasks(conn, append([]sql_builder.Filter{deletedFilter}, filters...))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list deleted tasks: %w", err)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DeletedAt.After(*tasks[j].DeletedAt)
+	})
+
+	return tasks, nil
+}
+
+// Moves the tasks in the trash matching the filters back to the task list.
+// Restored tasks keep the short id they had before they were deleted. The
+// `on-modify` hooks are run for each restored task.
+func Restore(filters []sql_builder.Filter) ([]int, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+	}
+
+	tasks, err := selectTasks(conn, append([]sql_builder.Filter{deletedFilter}, filters...))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+	}
+
+	var restored []Task
+	for _, task := range tasks {
+		updated := task
+		updated.DeletedAt = nil
+
+		updated, err := prepare(task, updated)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+		}
+
+		restored = append(restored, updated)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	ids := []int{}
+	for i, task := range restored {
+		if err := save(tx, tasks[i], task); err != nil {
+			return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+		}
+
+		ids = append(ids, task.ShortId)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to restore tasks: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Returns a filter matching tasks where the date selected by the key is before
+// the cutoff, or nil if the cutoff is zero.
+func beforeFilter(key string, cutoff time.Time) []sql_builder.Filter {
+	if cutoff.IsZero() {
+		return nil
+	}
+
+	return []sql_builder.Filter{{
+		Key:      fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER)", key),
+		Operator: sql_builder.Lte,
+		Value:    strconv.FormatInt(cutoff.Unix(), 10),
+	}}
+}
+
+// Lists the tasks in the trash matching the filters that were deleted before
+// the cutoff. A zero cutoff matches every task in the trash.
+func ListPurgeable(filters []sql_builder.Filter, cutoff time.Time) ([]Task, error) {
+	return ListTrash(append(beforeFilter("tasks.data ->> '$.deleted_at'", cutoff), filters...))
+}
+
+// Permanently removes the tasks in the trash matching the filters that were
+// deleted before the cutoff, along with their short ids. A zero cutoff
+// removes every matching task in the trash.
+func Purge(filters []sql_builder.Filter, cutoff time.Time) ([]int, error) {
+	conn, err := connect()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	filters = append(append([]sql_builder.Filter{deletedFilter}, beforeFilter("tasks.data ->> '$.deleted_at'", cutoff)...), filters...)
+	tasks, err := selectTasks(tx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		return []int{}, nil
+	}
+
+	shortIds := []int{}
+	for _, task := range tasks {
+		if err := recordChanges(tx, &task, nil); err != nil {
+			return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+		}
+
+		shortIds = append(shortIds, task.ShortId)
+	}
+
+	// The short ids are released along with the tasks
+	ids := TaskIdsFilter(tasks)
+	queries := []*sql_builder.Builder{
+		sql_builder.New().Delete("tasks").Filter(ids),
+		sql_builder.New().Delete("assignments").Filter(sql_builder.Filter{
+			Key:      "task_id",
+			Operator: ids.Operator,
+			Value:    ids.Value,
+		}),
+	}
+
+	for _, builder := range queries {
+		if os.Getenv("DEBUG") != "" {
+			log.Println(builder.SQL())
+		}
+
+		if _, err := tx.Exec(builder.SQL()); err != nil {
+			return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Failed to purge tasks: %w", err)
+	}
+
+	return shortIds, nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Sets the time a task in the trash was deleted.
+func setDeletedAt(t *testing.T, task Task, deletedAt time.Time) {
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	_, err = conn.Exec("UPDATE tasks SET data = json_set(data, '$.deleted_at', ?) WHERE id = ?", deletedAt.Format(time.RFC3339Nano), task.Id)
+	assert.NoError(t, err)
+}
+
+func TestDeleteAndRestore(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	milk := addTask(t, "Buy milk")
+	addTask(t, "Buy eggs")
+
+	filters := []sql_builder.Filter{TaskIdsFilter([]Task{milk})}
+	ids, err := Delete(filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+
+	// Deleted tasks keep their short id, so new tasks don't reuse it
+	addTask(t, "Walk dog")
+	assert.Equal(t, map[int]string{2: "Buy eggs", 3: "Walk dog"}, titlesByShortId(t))
+
+	trash, err := ListTrash(nil)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Equal(t, 1, trash[0].ShortId)
+	assert.NotNil(t, trash[0].DeletedAt)
+
+	ids, err = Restore(filters)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+	assert.Equal(t, map[int]string{1: "Buy milk", 2: "Buy eggs", 3: "Walk dog"}, titlesByShortId(t))
+
+	trash, err = ListTrash(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, trash)
+
+	// Only tasks in the trash are restored
+	ids, err = Restore(filters)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestPurge(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	old := addTask(t, "Buy milk")
+	recent := addTask(t, "Buy eggs")
+	addTask(t, "Walk dog")
+
+	_, err := Delete([]sql_builder.Filter{TaskIdsFilter([]Task{old, recent})})
+	assert.NoError(t, err)
+
+	now := time.Now()
+	setDeletedAt(t, old, now.AddDate(0, 0, -40))
+	setDeletedAt(t, recent, now.AddDate(0, 0, -5))
+
+	cutoff := now.AddDate(0, 0, -30)
+	purgeable, err := ListPurgeable(nil, cutoff)
+	assert.NoError(t, err)
+	assert.Len(t, purgeable, 1)
+	assert.Equal(t, "Buy milk", purgeable[0].Title)
+
+	ids, err := Purge(nil, cutoff)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, ids)
+
+	trash, err := ListTrash(nil)
+	assert.NoError(t, err)
+	assert.Len(t, trash, 1)
+	assert.Equal(t, "Buy eggs", trash[0].Title)
+
+	// A zero cutoff purges everything in the trash, but not the task list
+	ids, err = Purge(nil, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, ids)
+	assert.Equal(t, map[int]string{3: "Walk dog"}, titlesByShortId(t))
+
+	// The short ids of purged tasks are released along with the tasks
+	conn, err := connect()
+	assert.NoError(t, err)
+
+	var assignments []string
+	rows, err := conn.Query("SELECT task_id FROM assignments")
+	assert.NoError(t, err)
+
+	for rows.Next() {
+		var id string
+		assert.NoError(t, rows.Scan(&id))
+		assignments = append(assignments, id)
+	}
+
+	assert.NoError(t, rows.Close())
+	assert.Len(t, assignments, 1)
+	assert.NotContains(t, assignments, old.Id)
+	assert.NotContains(t, assignments, recent.Id)
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// The urgency added to tasks that have been started
+	activeUrgency = 4
+	// The urgency added to tasks once their scheduled date is reached
+	scheduledUrgency = 5
+)
+
+// Returns the urgency of the task, which determines its order in the task
+// list. Tasks with a higher urgency are listed first.
+func (task Task) Urgency(now time.Time) float64 {
+	urgency := priorityUrgency(task.Priority)
+
+	if task.Status == TaskStatusActive {
+		urgency += activeUrgency
+	}
+
+	if task.Scheduled != nil && !task.Scheduled.After(now) {
+		urgency += scheduledUrgency
+	}
+
+	return urgency
+}
+
+// Sorts tasks by their urgency, keeping tasks with the same urgency in their
+// existing order.
+func sortByUrgency(tasks []Task, now time.Time) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Urgency(now) > tasks[j].Urgency(now)
+	})
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUrgency(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 0, 1)
+
+	assert.Equal(t, 0.0, Task{}.Urgency(now))
+	assert.Equal(t, 6.0, Task{Priority: "H"}.Urgency(now))
+	assert.Equal(t, 4.0, Task{Status: TaskStatusActive}.Urgency(now))
+	assert.Equal(t, 5.0, Task{Scheduled: &past}.Urgency(now))
+	assert.Equal(t, 0.0, Task{Scheduled: &future}.Urgency(now))
+}
+
+func TestSortByUrgency(t *testing.T) {
+	now := time.Now()
+	past := now.AddDate(0, 0, -1)
+	tasks := []Task{
+		{Title: "a"},
+		{Title: "b", Priority: "L"},
+		{Title: "c"},
+		{Title: "d", Scheduled: &past},
+	}
+
+	sortByUrgency(tasks, now)
+
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+
+	assert.Equal(t, []string{"d", "b", "a", "c"}, titles)
+}
@@ -0,0 +1,164 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+type KeyCode int
+
+const (
+	// A printable character, stored in `Key.Rune`
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyDelete
+	KeyTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyCtrlC
+	KeyCtrlU
+	// Sent when the terminal is resized rather than when a key is pressed
+	KeyResize
+	KeyUnknown
+)
+
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+var escapeSequences = map[string]KeyCode{
+	"[A":  KeyUp,
+	"[B":  KeyDown,
+	"[C":  KeyRight,
+	"[D":  KeyLeft,
+	"[H":  KeyHome,
+	"[F":  KeyEnd,
+	"OA":  KeyUp,
+	"OB":  KeyDown,
+	"OC":  KeyRight,
+	"OD":  KeyLeft,
+	"OH":  KeyHome,
+	"OF":  KeyEnd,
+	"[1~": KeyHome,
+	"[3~": KeyDelete,
+	"[4~": KeyEnd,
+}
+
+// Parses the bytes read from the terminal into key presses. A lone escape
+// byte is treated as the escape key, while an escape byte followed by more
+// input is treated as an escape sequence for special keys such as the arrows.
+func ParseKeys(buf []byte) []Key {
+	var keys []Key
+
+	for len(buf) > 0 {
+		switch b := buf[0]; {
+		case b == 0x1b:
+			if len(buf) == 1 {
+				keys = append(keys, Key{Code: KeyEscape})
+				return keys
+			}
+
+			// Escape sequences end with a letter or `~`
+			end := 1
+			for end < len(buf) && end < 8 {
+				c := buf[end]
+				end++
+
+				if end > 2 && (c == '~' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')) {
+					break
+				}
+			}
+
+			if code, ok := escapeSequences[string(buf[1:end])]; ok {
+				keys = append(keys, Key{Code: code})
+			} else {
+				keys = append(keys, Key{Code: KeyUnknown})
+			}
+
+			buf = buf[end:]
+			continue
+
+		case b == '\r' || b == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case b == '\t':
+			keys = append(keys, Key{Code: KeyTab})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case b == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		case b == 0x15:
+			keys = append(keys, Key{Code: KeyCtrlU})
+		case b < 0x20:
+			keys = append(keys, Key{Code: KeyUnknown})
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, Key{Code: KeyRune, Rune: r})
+			buf = buf[size:]
+			continue
+		}
+
+		buf = buf[1:]
+	}
+
+	return keys
+}
+
+// Reads key presses from stdin until stdin is closed. Terminal resizes are
+// reported as `KeyResize` events. The terminal should be put in raw mode
+// using `MakeRaw` before reading keys.
+func ReadKeys() <-chan Key {
+	return readKeys(os.Stdin, notifyResize())
+}
+
+// Reads key presses from the reader until it is closed, merging them with the
+// resize events. Only the merging goroutine sends to the returned channel, so
+// it can close the channel without racing a resize.
+func readKeys(r io.Reader, resize <-chan os.Signal) <-chan Key {
+	keys := make(chan Key)
+	input := make(chan []Key)
+
+	go func() {
+		buf := make([]byte, 64)
+
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				input <- ParseKeys(buf[:n])
+			}
+
+			if err != nil {
+				close(input)
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(keys)
+
+		for {
+			select {
+			case parsed, ok := <-input:
+				if !ok {
+					return
+				}
+
+				for _, key := range parsed {
+					keys <- key
+				}
+			case <-resize:
+				keys <- Key{Code: KeyResize}
+			}
+		}
+	}()
+
+	return keys
+}
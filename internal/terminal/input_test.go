@@ -0,0 +1,58 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, ParseKeys([]byte("jk")), []Key{
+		{Code: KeyRune, Rune: 'j'},
+		{Code: KeyRune, Rune: 'k'},
+	})
+
+	assert.Equal(t, ParseKeys([]byte("é\r\x7f\t")), []Key{
+		{Code: KeyRune, Rune: 'é'},
+		{Code: KeyEnter},
+		{Code: KeyBackspace},
+		{Code: KeyTab},
+	})
+
+	assert.Equal(t, ParseKeys([]byte("\x1b")), []Key{{Code: KeyEscape}})
+	assert.Equal(t, ParseKeys([]byte("\x1b[A\x1b[Bq")), []Key{
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyRune, Rune: 'q'},
+	})
+
+	assert.Equal(t, ParseKeys([]byte("\x1b[3~\x1bOD")), []Key{
+		{Code: KeyDelete},
+		{Code: KeyLeft},
+	})
+}
+
+func TestReadKeys(t *testing.T) {
+	r, w := io.Pipe()
+	resize := make(chan os.Signal, 1)
+	keys := readKeys(r, resize)
+
+	resize <- os.Interrupt
+	assert.Equal(t, <-keys, Key{Code: KeyResize})
+
+	go func() {
+		w.Write([]byte("q"))
+		w.Close()
+	}()
+
+	assert.Equal(t, <-keys, Key{Code: KeyRune, Rune: 'q'})
+
+	_, ok := <-keys
+	assert.False(t, ok)
+
+	// Resizes after the input is closed are ignored rather than sent to the
+	// closed channel
+	resize <- os.Interrupt
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd || windows)
+
+package terminal
+
+import (
+	"errors"
+	"os"
+)
+
+func MakeRaw() (func(), error) {
+	return nil, errors.New("Raw mode is not supported on this platform")
+}
+
+func notifyResize() chan os.Signal {
+	return make(chan os.Signal)
+}
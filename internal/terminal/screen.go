@@ -0,0 +1,20 @@
+package terminal
+
+import "fmt"
+
+const (
+	EnterAltScreen = "\x1b[?1049h"
+	ExitAltScreen  = "\x1b[?1049l"
+	HideCursor     = "\x1b[?25l"
+	ShowCursor     = "\x1b[?25h"
+	ClearLine      = "\x1b[K"
+	ClearScreen    = "\x1b[2J"
+	Reverse        = "\x1b[7m"
+	Reset          = "\x1b[0m"
+)
+
+// Returns the escape sequence to move the cursor to a zero-indexed row and
+// column.
+func MoveTo(row int, col int) string {
+	return fmt.Sprintf("\x1b[%d;%dH", row+1, col+1)
+}
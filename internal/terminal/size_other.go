@@ -0,0 +1,7 @@
+//go:build !unix && !windows
+
+package terminal
+
+func size() (int, int, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,37 @@
+package terminal
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Returns true if stdout is connected to an interactive terminal.
+func IsTerminal() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// Returns the width and height of the terminal attached to stdout. The last
+// return value is false if stdout is not a terminal, in which case output
+// should not be constrained to any width. The `COLUMNS` and `LINES`
+// environment variables take precedence when set to allow overriding the
+// detected size.
+func Size() (int, int, bool) {
+	if !IsTerminal() {
+		return 0, 0, false
+	}
+
+	width, height, ok := size()
+
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		width, ok = columns, true
+	}
+
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		height = lines
+	}
+
+	return width, height, ok
+}
@@ -1,5 +1,10 @@
 package test_utils
 
+import (
+	"path/filepath"
+	"testing"
+)
+
 type Fixtures struct {
 	db string
 }
@@ -47,3 +52,12 @@ type Fixtures struct {
 // 	cmd := exec.Command("tsk", args)
 // 	cmd.Environ
 // }
+
+// Points the database and config directory at a temporary directory for the
+// duration of the test, so tests don't touch the real database or run the
+// user's hooks.
+func UseTempDB(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "tsk.db"))
+	t.Setenv("XDG_CONFIG_HOME", dir)
+}
@@ -0,0 +1,305 @@
+// Package todotxt reads and writes tasks in the todo.txt format
+// (https://github.com/todotxt/todo.txt).
+package todotxt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Keys that map to task fields rather than attributes. Priorities that can't
+// be written as a todo.txt priority and the priority of completed tasks are
+// written with the `pri:` key, as is convention in other todo.txt tools.
+const (
+	idKey        = "id"
+	dueKey       = "due"
+	scheduledKey = "scheduled"
+	priorityKey  = "pri"
+	statusKey    = "status"
+	// The threshold date, which hides tasks until the date in other todo.txt
+	// tools like the wait date.
+	waitKey = "t"
+)
+
+// Returns the date field of the task stored with the key, if any.
+func dateField(task *storage.Task, key string) (**time.Time, bool) {
+	switch key {
+	case dueKey:
+		return &task.Due, true
+	case scheduledKey:
+		return &task.Scheduled, true
+	case waitKey:
+		return &task.Wait, true
+	default:
+		return nil, false
+	}
+}
+
+// Maps the configured priorities to todo.txt priorities by their rank, so the
+// highest priority is `A`, the next is `B`, and so on.
+func toLetter(priority string) (string, bool) {
+	if rank := storage.PriorityRank(priority); rank >= 0 && rank < 26 {
+		return string(rune('A' + rank)), true
+	}
+
+	return "", false
+}
+
+// Maps a todo.txt priority to the configured priority with the same rank.
+// Values that are already a configured priority, or letters without a
+// matching rank, are returned as is.
+func fromLetter(letter string) string {
+	if storage.PriorityRank(letter) >= 0 || len(letter) != 1 {
+		return letter
+	}
+
+	levels := storage.Priorities()
+	if rank := int(letter[0] - 'A'); rank >= 0 && rank < len(levels) {
+		return levels[rank]
+	}
+
+	return letter
+}
+
+// Returns true if the first word of a line would be read as the completion
+// marker, a priority, or a date.
+func isPrefix(word string) bool {
+	_, isDate := parseDate(word)
+	return word == "x" || isDate || len(word) == 3 && word[0] == '(' && word[2] == ')'
+}
+
+// Escapes the words of a title that would otherwise be read as a project, tag,
+// or attribute by prefixing them with a `\`. The first word is also escaped if
+// it could be read as a prefix when the dates before it are missing.
+func escapeTitle(title string) string {
+	words := strings.Fields(title)
+
+	for i, word := range words {
+		_, _, isAttribute := parseAttribute(word)
+
+		if strings.HasPrefix(word, "\\") ||
+			len(word) > 1 && (word[0] == '+' || word[0] == '@') ||
+			isAttribute ||
+			i == 0 && isPrefix(word) {
+			words[i] = "\\" + word
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Formats a task as a single todo.txt line.
+func format(task storage.Task) string {
+	var parts []string
+	attributes := map[string]string{idKey: task.Id}
+
+	for key, value := range task.Attributes {
+		attributes[key] = value
+	}
+
+	if task.Status == storage.TaskStatusDone {
+		parts = append(parts, "x")
+
+		if task.CompletedAt != nil {
+			parts = append(parts, task.CompletedAt.Local().Format(time.DateOnly))
+		}
+	}
+
+	if letter, ok := toLetter(task.Priority); ok && task.Status != storage.TaskStatusDone {
+		parts = append(parts, fmt.Sprintf("(%s)", letter))
+	} else if task.Priority != "" {
+		attributes[priorityKey] = task.Priority
+	}
+
+	if task.Status == storage.TaskStatusActive {
+		attributes[statusKey] = string(task.Status)
+	}
+
+	for _, key := range []string{dueKey, scheduledKey, waitKey} {
+		if field, _ := dateField(&task, key); *field != nil {
+			attributes[key] = (*field).Local().Format(time.DateOnly)
+		}
+	}
+
+	// The creation date is required if the completion date is included
+	if !task.CreatedAt.IsZero() && (task.CompletedAt != nil || task.Status != storage.TaskStatusDone) {
+		parts = append(parts, task.CreatedAt.Local().Format(time.DateOnly))
+	}
+
+	parts = append(parts, escapeTitle(task.Title))
+
+	if task.Project != "" {
+		parts = append(parts, "+"+task.Project)
+	}
+
+	for _, tag := range task.Tags {
+		parts = append(parts, "@"+tag)
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := attributes[key]; value != "" {
+			parts = append(parts, key+":"+value)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Writes the tasks in the todo.txt format, one task per line.
+func Write(w io.Writer, tasks []storage.Task) error {
+	b := bufio.NewWriter(w)
+
+	for _, task := range tasks {
+		b.WriteString(format(task))
+		b.WriteByte('\n')
+	}
+
+	return b.Flush()
+}
+
+func parseDate(word string) (time.Time, bool) {
+	t, err := time.ParseInLocation(time.DateOnly, word, time.Local)
+	return t, err == nil
+}
+
+// Parses a `key:value` word. URLs are not treated as attributes.
+func parseAttribute(word string) (string, string, bool) {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+
+	return key, value, true
+}
+
+func setAttribute(task *storage.Task, key string, value string) {
+	if task.Attributes == nil {
+		task.Attributes = make(map[string]string)
+	}
+
+	task.Attributes[key] = value
+}
+
+// Parses a single todo.txt line as a task. Tasks without an `id:` have an
+// empty id.
+func parse(line string) storage.Task {
+	task := storage.Task{
+		Status: storage.TaskStatusPending,
+		Tags:   make([]string, 0),
+	}
+
+	words := strings.Fields(line)
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Status = storage.TaskStatusDone
+		words = words[1:]
+
+		if len(words) > 0 {
+			if completed, ok := parseDate(words[0]); ok {
+				task.CompletedAt = &completed
+				words = words[1:]
+			}
+		}
+	}
+
+	if len(words) > 0 && len(words[0]) == 3 && words[0][0] == '(' && words[0][2] == ')' {
+		if letter := words[0][1]; letter >= 'A' && letter <= 'Z' {
+			task.Priority = fromLetter(string(letter))
+			words = words[1:]
+		}
+	}
+
+	if len(words) > 0 {
+		if created, ok := parseDate(words[0]); ok {
+			task.CreatedAt = created
+			words = words[1:]
+		}
+	}
+
+	var title []string
+	for _, word := range words {
+		// Escaped words are always part of the title
+		if len(word) > 1 && word[0] == '\\' {
+			title = append(title, word[1:])
+			continue
+		}
+
+		if project, ok := strings.CutPrefix(word, "+"); ok && project != "" {
+			// Tasks only have one project, so any other projects are kept as
+			// tags.
+			if task.Project == "" {
+				task.Project = project
+			} else {
+				task.Tags = append(task.Tags, project)
+			}
+
+			continue
+		}
+
+		if tag, ok := strings.CutPrefix(word, "@"); ok && tag != "" {
+			task.Tags = append(task.Tags, tag)
+			continue
+		}
+
+		if key, value, ok := parseAttribute(word); ok {
+			switch key {
+			case idKey:
+				task.Id = value
+			case priorityKey:
+				task.Priority = fromLetter(value)
+			case dueKey, scheduledKey, waitKey:
+				// Dates that aren't valid are kept as attributes
+				if date, ok := parseDate(value); ok {
+					field, _ := dateField(&task, key)
+					*field = &date
+					continue
+				}
+
+				setAttribute(&task, key, value)
+			case statusKey:
+				if value == string(storage.TaskStatusActive) && task.Status != storage.TaskStatusDone {
+					task.Status = storage.TaskStatusActive
+				}
+			default:
+				setAttribute(&task, key, value)
+			}
+
+			continue
+		}
+
+		title = append(title, word)
+	}
+
+	task.Title = strings.Join(title, " ")
+	return task
+}
+
+// Reads tasks from the todo.txt format, ignoring blank lines.
+func Read(r io.Reader) ([]storage.Task, error) {
+	tasks := []storage.Task{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			tasks = append(tasks, parse(line))
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read todo.txt: %w", err)
+	}
+
+	return tasks, nil
+}
@@ -0,0 +1,141 @@
+package todotxt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestParse(t *testing.T) {
+	completed := date(2026, 1, 2)
+	due := date(2026, 11, 1)
+
+	assert.Equal(t, storage.Task{
+		Title:    "Call mom",
+		Priority: "H",
+		Status:   storage.TaskStatusPending,
+		Project:  "family",
+		Tags:     []string{"phone"},
+		Attributes: map[string]string{
+			"size": "small",
+		},
+		Due:       &due,
+		CreatedAt: date(2025, 12, 30),
+	}, parse("(A) 2025-12-30 Call mom +family @phone due:2026-11-01 size:small"))
+
+	assert.Equal(t, storage.Task{
+		Id:          "abc",
+		Title:       "Pay bills",
+		Priority:    "M",
+		Status:      storage.TaskStatusDone,
+		Tags:        []string{"home"},
+		CreatedAt:   date(2026, 1, 1),
+		CompletedAt: &completed,
+	}, parse("x 2026-01-02 2026-01-01 Pay bills @home id:abc pri:B"))
+
+	assert.Equal(t, storage.Task{
+		Title:  "Read https://example.com",
+		Status: storage.TaskStatusActive,
+		Tags:   []string{},
+	}, parse("Read https://example.com status:active"))
+}
+
+func TestRoundTrip(t *testing.T) {
+	completed := date(2026, 1, 2)
+	due := date(2026, 11, 1)
+	wait := date(2026, 10, 20)
+	tasks := []storage.Task{
+		{
+			Id:         "a",
+			Title:      "Call mom",
+			Priority:   "H",
+			Status:     storage.TaskStatusActive,
+			Project:    "family",
+			Tags:       []string{"phone"},
+			Attributes: map[string]string{"size": "small"},
+			Due:        &due,
+			Wait:       &wait,
+			CreatedAt:  date(2025, 12, 30),
+		},
+		{
+			Id:          "b",
+			Title:       "Pay bills",
+			Priority:    "urgent",
+			Status:      storage.TaskStatusDone,
+			Tags:        []string{},
+			CreatedAt:   date(2026, 1, 1),
+			CompletedAt: &completed,
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tasks))
+	assert.Equal(
+		t,
+		"(A) 2025-12-30 Call mom +family @phone due:2026-11-01 id:a size:small status:active t:2026-10-20\n"+
+			"x 2026-01-02 2026-01-01 Pay bills id:b pri:urgent\n",
+		buf.String(),
+	)
+
+	result, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+func TestRoundTripEscapedTitle(t *testing.T) {
+	titles := []string{
+		"Meeting at 10:30 with Sam",
+		"Reply to @sam about +1 votes",
+		"x marks the spot",
+		"(A) is the top priority",
+		"2026-01-01 retro notes",
+		"Escape \\ and \\+tag literally",
+	}
+
+	var tasks []storage.Task
+	for i, title := range titles {
+		tasks = append(tasks, storage.Task{
+			Id:     string(rune('a' + i)),
+			Title:  title,
+			Status: storage.TaskStatusPending,
+			Tags:   []string{},
+		})
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tasks))
+	assert.Contains(t, buf.String(), "Meeting at \\10:30 with Sam id:a\n")
+
+	result, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+func TestCustomPriorities(t *testing.T) {
+	defer storage.SetPriorities([]string{"H", "M", "L"})
+	assert.NoError(t, storage.SetPriorities([]string{"P0", "P1", "P2"}))
+
+	tasks := []storage.Task{
+		{Id: "a", Title: "Fix outage", Priority: "P0", Status: storage.TaskStatusPending, Tags: []string{}},
+		{Id: "b", Title: "Write docs", Priority: "P2", Status: storage.TaskStatusPending, Tags: []string{}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, tasks))
+	assert.Equal(t, "(A) Fix outage id:a\n(C) Write docs id:b\n", buf.String())
+
+	result, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+
+	assert.Equal(t, "P0", parse("(B) Review pri:P0").Priority)
+	assert.Equal(t, "P1", parse("x Review pri:B").Priority)
+	assert.Equal(t, "D", parse("(D) Review").Priority)
+}
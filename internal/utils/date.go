@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Returns the start of the day containing the time.
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Parses a date relative to now. Dates can be absolute (`2024-01-31`), named
+// (`today`, `tomorrow`, `yesterday`), the next occurrence of a weekday
+// (`fri`), or an offset from today (`3d`, `2w`, `1mo`, `1y`). All dates are
+// the start of the day in the local time zone.
+func ParseDate(str string, now time.Time) (time.Time, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	today := StartOfDay(now.Local())
+
+	if t, err := time.ParseInLocation(time.DateOnly, str, time.Local); err == nil {
+		return t, nil
+	}
+
+	switch str {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if weekday, ok := weekdays[str]; ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if years, months, days, ok := parseOffset(str); ok {
+		return today.AddDate(years, months, days), nil
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid date \"%s\"", str)
+}
+
+// Parses an age (`30d`, `2w`, `6mo`, `1y`) returning the time that long before
+// now.
+func ParseAge(str string, now time.Time) (time.Time, error) {
+	years, months, days, ok := parseOffset(strings.ToLower(strings.TrimSpace(str)))
+	if !ok || years < 0 || months < 0 || days < 0 {
+		return time.Time{}, fmt.Errorf("Invalid age \"%s\", expected a number of days (30d), weeks (2w), months (6mo), or years (1y)", str)
+	}
+
+	return now.AddDate(-years, -months, -days), nil
+}
+
+// Parses an offset such as `3d`, `2w`, `1mo`, or `1y` returning the number of
+// years, months, and days.
+func parseOffset(str string) (int, int, int, bool) {
+	for _, unit := range []string{"mo", "d", "w", "y"} {
+		if value, ok := strings.CutSuffix(str, unit); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				break
+			}
+
+			switch unit {
+			case "d":
+				return 0, 0, n, true
+			case "w":
+				return 0, 0, 7 * n, true
+			case "mo":
+				return 0, n, 0, true
+			case "y":
+				return n, 0, 0, true
+			}
+		}
+	}
+
+	return 0, 0, 0, false
+}
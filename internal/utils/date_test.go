@@ -0,0 +1,65 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 1, 31, 15, 30, 0, 0, time.Local)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	cases := map[string]time.Time{
+		"2024-03-15": date(2024, 3, 15),
+		"today":      date(2024, 1, 31),
+		"Tomorrow":   date(2024, 2, 1),
+		"yesterday":  date(2024, 1, 30),
+		"fri":        date(2024, 2, 2),
+		"wednesday":  date(2024, 2, 7),
+		"3d":         date(2024, 2, 3),
+		"-1d":        date(2024, 1, 30),
+		"2w":         date(2024, 2, 14),
+		"1mo":        date(2024, 3, 2),
+		"1y":         date(2025, 1, 31),
+	}
+
+	for input, expected := range cases {
+		result, err := utils.ParseDate(input, now)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, result, input)
+	}
+
+	_, err := utils.ParseDate("someday", now)
+	assert.EqualError(t, err, "Invalid date \"someday\"")
+
+	_, err = utils.ParseDate("xd", now)
+	assert.Error(t, err)
+}
+
+func TestParseAge(t *testing.T) {
+	now := time.Date(2024, 3, 31, 15, 30, 0, 0, time.Local)
+
+	cases := map[string]time.Time{
+		"30d": time.Date(2024, 3, 1, 15, 30, 0, 0, time.Local),
+		"2w":  time.Date(2024, 3, 17, 15, 30, 0, 0, time.Local),
+		"1MO": time.Date(2024, 3, 2, 15, 30, 0, 0, time.Local),
+		"1y":  time.Date(2023, 3, 31, 15, 30, 0, 0, time.Local),
+	}
+
+	for input, expected := range cases {
+		result, err := utils.ParseAge(input, now)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, result, input)
+	}
+
+	for _, input := range []string{"", "today", "-1d", "30"} {
+		_, err := utils.ParseAge(input, now)
+		assert.Error(t, err, input)
+	}
+}
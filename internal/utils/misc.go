@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
@@ -39,3 +41,21 @@ func GenerateId() string {
 
 	return string(b)
 }
+
+// Returns the id unchanged if it only uses the characters of generated ids.
+// Other ids, such as iCalendar UIDs from other apps, are replaced by an id
+// derived from their hash so importing them again matches the same task.
+func NormalizeId(id string) string {
+	if strings.Trim(id, charset) == "" {
+		return id
+	}
+
+	sum := sha256.Sum256([]byte(id))
+	b := make([]byte, 8)
+
+	for i := range b {
+		b[i] = charset[int(sum[i])%len(charset)]
+	}
+
+	return string(b)
+}
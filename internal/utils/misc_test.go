@@ -23,3 +23,13 @@ func TestBulk(t *testing.T) {
 	assert.Equal(t, utils.IsBulk(context, 4), true)
 	assert.Equal(t, utils.IsBulk(context, 10), true)
 }
+
+func TestNormalizeId(t *testing.T) {
+	assert.Equal(t, utils.NormalizeId("aB3dE6gH"), "aB3dE6gH")
+	assert.Equal(t, utils.NormalizeId(""), "")
+
+	id := utils.NormalizeId("it's-1@example.com")
+	assert.Regexp(t, "^[a-zA-Z0-9]{8}$", id)
+	assert.Equal(t, utils.NormalizeId("it's-1@example.com"), id)
+	assert.NotEqual(t, utils.NormalizeId("it's-2@example.com"), id)
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/config"
+	"github.com/mskelton/tsk/internal/hooks"
+	"github.com/mskelton/tsk/internal/printer"
 	"github.com/mskelton/tsk/pkg/cmd"
 )
 
@@ -12,6 +15,38 @@ func main() {
 	parser := arg_parser.New()
 	context := parser.Parse(args)
 
+	// Config overrides from the command line are applied after the config
+	// file so they take precedence.
+	configs, err := config.Load()
+	if err != nil {
+		printer.Error(err)
+	}
+
+	context.Config = append(configs, context.Config...)
+	cmd.Configure(context)
+
+	// Completions need to be fast and free of side effects, so hooks and
+	// automatic archiving are not run for them.
+	useHooks := context.Command != arg_parser.Complete
+
+	if useHooks {
+		if _, err := hooks.Run(hooks.OnLaunch); err != nil {
+			printer.Error(err)
+		}
+
+		cmd.AutoArchive(context)
+	}
+
+	run(context)
+
+	if useHooks {
+		if _, err := hooks.Run(hooks.OnExit); err != nil {
+			printer.Error(err)
+		}
+	}
+}
+
+func run(context arg_parser.ParseContext) {
 	switch context.Command {
 	case arg_parser.List:
 		cmd.List(context)
@@ -29,8 +64,56 @@ func main() {
 		cmd.Stop(context)
 	case arg_parser.Get:
 		cmd.Get(context)
+	case arg_parser.Duplicate:
+		cmd.Duplicate(context)
+	case arg_parser.Subtask:
+		cmd.Subtask(context)
+	case arg_parser.Check:
+		cmd.Check(context)
+	case arg_parser.Uncheck:
+		cmd.Uncheck(context)
 	case arg_parser.Delete:
 		cmd.Delete(context)
+	case arg_parser.Trash:
+		cmd.Trash(context)
+	case arg_parser.Restore:
+		cmd.Restore(context)
+	case arg_parser.Purge:
+		cmd.Purge(context)
+	case arg_parser.Archive:
+		cmd.Archive(context)
+	case arg_parser.Search:
+		cmd.Search(context)
+	case arg_parser.UI:
+		cmd.UI(context)
+	case arg_parser.Tags:
+		cmd.Tags(context)
+	case arg_parser.Tag:
+		cmd.Tag(context)
+	case arg_parser.Blueprint:
+		cmd.Blueprint(context)
+	case arg_parser.Sync:
+		cmd.Sync(context)
+	case arg_parser.Serve:
+		cmd.Serve(context)
+	case arg_parser.Export:
+		cmd.Export(context)
+	case arg_parser.Import:
+		cmd.Import(context)
+	case arg_parser.Waiting:
+		cmd.Waiting(context)
+	case arg_parser.Stats:
+		cmd.Stats(context)
+	case arg_parser.Burndown:
+		cmd.Burndown(context)
+	case arg_parser.History:
+		cmd.History(context)
+	case arg_parser.Calendar:
+		cmd.Calendar(context)
+	case arg_parser.Completion:
+		cmd.Completion(context)
+	case arg_parser.Complete:
+		cmd.Complete(context)
 	case arg_parser.Help:
 		cmd.Help()
 	case arg_parser.Version:
@@ -52,5 +135,4 @@ func main() {
 			cmd.List(context)
 		}
 	}
-
 }
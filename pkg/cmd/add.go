@@ -3,29 +3,125 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
 	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
 	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
 )
 
-func Add(ctx arg_parser.ParseContext) {
-	task := storage.NewTask()
+// Parses the date of a scoped arg, exiting if the date is invalid.
+func parseDateArg(value string) *time.Time {
+	date, err := utils.ParseDate(value, time.Now())
+	if err != nil {
+		printer.Error(err)
+	}
+
+	return &date
+}
+
+// Parses the priority of a scoped arg, exiting if the priority isn't one of
+// the configured priorities.
+func parsePriorityArg(value string) string {
+	priority, err := storage.ParsePriority(value)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	return priority
+}
+
+// Returns the unique id of the task with the short id of a `parent:` arg,
+// exiting if the task doesn't exist. An empty id removes the parent.
+func parentArg(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		printer.Error(fmt.Errorf("Invalid parent \"%s\", expected a task id", value))
+	}
+
+	tasks, err := storage.GetTasks([]sql_builder.Filter{shortIdsFilter([]int{id})})
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if len(tasks) == 0 {
+		printer.Error(fmt.Errorf("Parent task %d does not exist", id))
+	}
+
+	return tasks[0].Id
+}
+
+// Applies the presets of a blueprint to a task, exiting if the blueprint
+// doesn't exist.
+func applyBlueprint(task *storage.Task, name string) {
+	blueprint, err := storage.GetBlueprint(name)
+	if errors.Is(err, storage.ErrBlueprintNotFound) {
+		printer.Error(fmt.Errorf("Blueprint \"%s\" does not exist", name))
+	} else if err != nil {
+		printer.Error(err)
+	}
+
+	if err := blueprint.Apply(task, time.Now()); err != nil {
+		printer.Error(err)
+	}
+}
+
+// Applies the title, tags, and scoped args to a task. Tags prefixed with `-`
+// are removed from the task.
+func applyArgs(task *storage.Task, ctx arg_parser.ParseContext) {
+	// Blueprints are applied first so the other args override them
+	for _, arg := range ctx.Args {
+		if v, ok := arg.(arg_parser.ScopedArg); ok && v.Scope == arg_parser.ScopeFrom {
+			applyBlueprint(task, v.Value)
+		}
+	}
 
 	for _, arg := range ctx.Args {
 		switch v := arg.(type) {
 		case arg_parser.TextArg:
 			task.Title = v.Text
 		case arg_parser.TagArg:
-			task.Tags = append(task.Tags, v.Tag)
+			if v.Operator == arg_parser.Exclude {
+				task.Tags = storage.ReplaceTagIn(task.Tags, v.Tag, "")
+			} else if !slices.Contains(task.Tags, v.Tag) {
+				task.Tags = append(task.Tags, v.Tag)
+			}
 		case arg_parser.ScopedArg:
-			if v.Scope == arg_parser.ScopePriority {
-				task.Priority = v.Value
-			} else {
+			switch v.Scope {
+			case arg_parser.ScopePriority:
+				task.Priority = parsePriorityArg(v.Value)
+			case arg_parser.ScopeProject:
+				task.Project = v.Value
+			case arg_parser.ScopeDue:
+				task.Due = parseDateArg(v.Value)
+			case arg_parser.ScopeScheduled:
+				task.Scheduled = parseDateArg(v.Value)
+			case arg_parser.ScopeWait:
+				task.Wait = parseDateArg(v.Value)
+			case arg_parser.ScopeParent:
+				task.Parent = parentArg(v.Value)
+			case arg_parser.ScopeFrom:
+				// Applied before the other args
+			case arg_parser.ScopePriorityAbove, arg_parser.ScopePriorityBelow:
+				printer.Error(fmt.Errorf("\"%s:\" can only be used as a filter", v.Scope))
+			default:
 				printer.Error(fmt.Errorf("Missing value for \"%s:\"", v.Scope))
 			}
 		}
 	}
+}
+
+func Add(ctx arg_parser.ParseContext) {
+	task := storage.NewTask()
+	applyArgs(&task, ctx)
 
 	if task.Title == "" {
 		printer.Error(errors.New("Missing title"))
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Moves the completed tasks matching the filters to the archive. The
+// `older-than:` arg limits the tasks to those completed before the given age.
+func Archive(ctx arg_parser.ParseContext) {
+	cutoff, err := getOlderThan(ctx)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	tasks, err := storage.Archive(buildFilters(ctx), cutoff)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	count := len(tasks)
+	if count == 0 {
+		printer.Message("No completed tasks to archive")
+		return
+	}
+
+	fmt.Printf("Archived %d %s\n", count, utils.Pluralize(count, "task", "tasks"))
+}
+
+// Archives the tasks completed before the age set with the `archive.after=`
+// config override. This runs on every launch so completed tasks are archived
+// without running the archive command.
+func AutoArchive(ctx arg_parser.ParseContext) {
+	after := ""
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.ArchiveConfig); ok {
+			after = config.After
+		}
+	}
+
+	if after == "" {
+		return
+	}
+
+	cutoff, err := utils.ParseAge(after, time.Now())
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if _, err := storage.Archive(nil, cutoff); err != nil {
+		printer.Error(err)
+	}
+}
+
+// Returns true if archived tasks should be included in reports, configured
+// with the `archived=` config override.
+func includeArchived(ctx arg_parser.ParseContext) bool {
+	include := false
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.ArchivedConfig); ok {
+			include = config.Include
+		}
+	}
+
+	return include
+}
+
+// Gets the tasks matching the filters for reports, including archived tasks
+// when using `archived=yes`.
+func reportTasks(ctx arg_parser.ParseContext) []storage.Task {
+	filters := buildFilters(ctx)
+
+	tasks, err := storage.GetTasks(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if includeArchived(ctx) {
+		archived, err := storage.GetArchivedTasks(filters)
+		if err != nil {
+			printer.Error(err)
+		}
+
+		tasks = append(tasks, archived...)
+	}
+
+	return tasks
+}
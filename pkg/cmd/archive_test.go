@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func reportTitles(ctx arg_parser.ParseContext) []string {
+	titles := []string{}
+	for _, task := range reportTasks(ctx) {
+		titles = append(titles, task.Title)
+	}
+
+	return titles
+}
+
+func TestAutoArchive(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Buy milk", "Buy eggs")
+
+	_, err := storage.Edit(
+		[]sql_builder.Filter{shortIdsFilter([]int{1})},
+		[]storage.QueryEdit{{Path: "status", Value: string(storage.TaskStatusDone)}},
+	)
+	assert.NoError(t, err)
+
+	// Tasks completed more recently than `archive.after` are not archived
+	AutoArchive(arg_parser.ParseContext{Config: []arg_parser.Config{arg_parser.ArchiveConfig{After: "1d"}}})
+	assert.ElementsMatch(t, []string{"Buy milk", "Buy eggs"}, reportTitles(arg_parser.ParseContext{}))
+
+	// Without `archive.after`, nothing is archived
+	AutoArchive(arg_parser.ParseContext{})
+	assert.ElementsMatch(t, []string{"Buy milk", "Buy eggs"}, reportTitles(arg_parser.ParseContext{}))
+
+	AutoArchive(arg_parser.ParseContext{Config: []arg_parser.Config{arg_parser.ArchiveConfig{After: "0d"}}})
+	assert.Equal(t, []string{"Buy eggs"}, reportTitles(arg_parser.ParseContext{}))
+
+	// Reports include archived tasks with `archived=yes`
+	parser := arg_parser.New()
+	assert.ElementsMatch(t, []string{"Buy milk", "Buy eggs"}, reportTitles(parser.Parse([]string{"archived=yes", "stats"})))
+	assert.Equal(t, []string{"Buy eggs"}, reportTitles(parser.Parse([]string{"archived=no", "stats"})))
+	assert.Equal(t, []string{"Buy milk"}, reportTitles(parser.Parse([]string{"archived=yes", "milk", "stats"})))
+}
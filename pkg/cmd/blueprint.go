@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Saves, lists, or deletes blueprints.
+func Blueprint(ctx arg_parser.ParseContext) {
+	words := strings.Fields(firstTextArg(ctx))
+	if len(words) == 0 {
+		words = []string{"list"}
+	}
+
+	switch words[0] {
+	case "list":
+		listBlueprints(ctx)
+	case "save":
+		saveBlueprint(ctx, words[1:])
+	case "delete":
+		deleteBlueprint(words[1:])
+	default:
+		printer.Error(fmt.Errorf("Invalid action \"%s\", expected one of save, list, or delete", words[0]))
+	}
+}
+
+// Saves a blueprint from the args, which use the same syntax as adding a task.
+// If a task is selected with filters, the blueprint starts with the priority,
+// project, tags, attributes, and checklist of the task.
+func saveBlueprint(ctx arg_parser.ParseContext, words []string) {
+	if len(words) == 0 {
+		printer.Error(errors.New("Usage: tsk blueprint save <name> <args>"))
+	}
+
+	blueprint := storage.Blueprint{Tags: []string{}}
+
+	if len(ctx.Filters) > 0 {
+		task := singleTask(ctx, "blueprint")
+
+		blueprint.Priority = task.Priority
+		blueprint.Project = task.Project
+		blueprint.Tags = append(blueprint.Tags, task.Tags...)
+		blueprint.Attributes = maps.Clone(task.Attributes)
+
+		for _, item := range task.Checklist {
+			blueprint.Checklist = append(blueprint.Checklist, item.Text)
+		}
+	}
+
+	for _, arg := range ctx.Args {
+		switch v := arg.(type) {
+		case arg_parser.TagArg:
+			if v.Operator == arg_parser.Exclude {
+				blueprint.Tags = storage.ReplaceTagIn(blueprint.Tags, v.Tag, "")
+			} else if !slices.Contains(blueprint.Tags, v.Tag) {
+				blueprint.Tags = append(blueprint.Tags, v.Tag)
+			}
+		case arg_parser.ScopedArg:
+			switch v.Scope {
+			case arg_parser.ScopePriority:
+				blueprint.Priority = parsePriorityArg(v.Value)
+			case arg_parser.ScopeProject:
+				blueprint.Project = v.Value
+			case arg_parser.ScopeDue, arg_parser.ScopeScheduled, arg_parser.ScopeWait:
+				// Dates are saved as written so they are relative to the day
+				// each task is created.
+				if _, err := utils.ParseDate(v.Value, time.Now()); err != nil {
+					printer.Error(err)
+				}
+
+				switch v.Scope {
+				case arg_parser.ScopeDue:
+					blueprint.Due = v.Value
+				case arg_parser.ScopeScheduled:
+					blueprint.Scheduled = v.Value
+				case arg_parser.ScopeWait:
+					blueprint.Wait = v.Value
+				}
+			default:
+				printer.Error(fmt.Errorf("Blueprints don't support \"%s:\"", v.Scope))
+			}
+		}
+	}
+
+	blueprint.Name = words[0]
+	blueprint.Title = strings.Join(words[1:], " ")
+
+	replaced, err := storage.SaveBlueprint(blueprint)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if replaced {
+		fmt.Printf("Updated blueprint %s\n", blueprint.Name)
+	} else {
+		fmt.Printf("Saved blueprint %s\n", blueprint.Name)
+	}
+}
+
+func listBlueprints(ctx arg_parser.ParseContext) {
+	format := getFormat(ctx)
+	blueprints, err := storage.ListBlueprints()
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if format.IsData() {
+		printer.JSON(format, blueprints)
+		return
+	}
+
+	if len(blueprints) == 0 && format == printer.FormatTable {
+		printer.Message("No blueprints found")
+		return
+	}
+
+	table := printer.Table{
+		Columns:  []string{"Name", "P", "Project", "Due", "Tags", "Checklist", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, blueprint := range blueprints {
+		checklist := ""
+		if len(blueprint.Checklist) > 0 {
+			checklist = strconv.Itoa(len(blueprint.Checklist))
+		}
+
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				blueprint.Name,
+				blueprint.Priority,
+				blueprint.Project,
+				blueprint.Due,
+				strings.Join(blueprint.Tags, " "),
+				checklist,
+				blueprint.Title,
+			},
+			Style: printer.RuleStyle(blueprint.Priority, blueprint.Tags),
+		})
+	}
+
+	table.PrintAs(format)
+}
+
+func deleteBlueprint(words []string) {
+	if len(words) != 1 {
+		printer.Error(errors.New("Usage: tsk blueprint delete <name>"))
+	}
+
+	err := storage.DeleteBlueprint(words[0])
+	if errors.Is(err, storage.ErrBlueprintNotFound) {
+		printer.Error(fmt.Errorf("Blueprint \"%s\" does not exist", words[0]))
+	} else if err != nil {
+		printer.Error(err)
+	}
+
+	fmt.Printf("Deleted blueprint %s\n", words[0])
+}
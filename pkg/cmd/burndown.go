@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+type period string
+
+const (
+	daily   period = "daily"
+	weekly  period = "weekly"
+	monthly period = "monthly"
+)
+
+// The number of periods shown in charts
+var periodCounts = map[period]int{daily: 14, weekly: 12, monthly: 12}
+
+// Returns the period from the command args, defaulting to weekly.
+func getPeriod(ctx arg_parser.ParseContext) period {
+	text := firstTextArg(ctx)
+	if text == "" {
+		return weekly
+	}
+
+	p := period(text)
+	if _, ok := periodCounts[p]; !ok {
+		printer.Error(fmt.Errorf("Invalid period \"%s\", expected one of daily, weekly, or monthly", text))
+	}
+
+	return p
+}
+
+// Returns the start of the period containing the time.
+func (p period) start(t time.Time) time.Time {
+	t = t.Local()
+
+	switch p {
+	case daily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	case monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	default:
+		return startOfWeek(t)
+	}
+}
+
+// Returns the start of the period after the period starting at the time.
+func (p period) next(start time.Time) time.Time {
+	switch p {
+	case daily:
+		return start.AddDate(0, 0, 1)
+	case monthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 7)
+	}
+}
+
+func (p period) label(start time.Time) string {
+	if p == monthly {
+		return start.Format("2006-01")
+	}
+
+	return start.Format(time.DateOnly)
+}
+
+// Returns the start of each period shown in a chart, oldest first.
+func (p period) starts(now time.Time) []time.Time {
+	count := periodCounts[p]
+	starts := make([]time.Time, count)
+	start := p.start(now)
+
+	for i := count - 1; i >= 0; i-- {
+		starts[i] = start
+
+		// Step back from just before the start of this period to avoid
+		// issues with DST and month lengths.
+		start = p.start(start.Add(-time.Hour))
+	}
+
+	return starts
+}
+
+func chartTasks(ctx arg_parser.ParseContext) []storage.Task {
+	tasks := reportTasks(ctx)
+	if len(tasks) == 0 {
+		printer.Error(fmt.Errorf("No tasks match filters"))
+	}
+
+	return tasks
+}
+
+// Returns true if the task was completed before the time.
+func completedBefore(task storage.Task, t time.Time) bool {
+	return task.Status == storage.TaskStatusDone && completionTime(task).Before(t)
+}
+
+// Draws a chart of the number of pending and completed tasks at the end of
+// each period.
+func Burndown(ctx arg_parser.ParseContext) {
+	p := getPeriod(ctx)
+	tasks := chartTasks(ctx)
+	theme := printer.CurrentTheme()
+
+	chart := printer.Chart{
+		Series: []printer.Series{
+			{Name: "Pending", Style: theme.ChartPending, Symbol: "+"},
+			{Name: "Done", Style: theme.ChartDone, Symbol: "X"},
+		},
+		Stacked: true,
+	}
+
+	for _, start := range p.starts(time.Now()) {
+		end := p.next(start)
+		pending, done := 0, 0
+
+		for _, task := range tasks {
+			if !task.CreatedAt.Before(end) {
+				continue
+			}
+
+			if completedBefore(task, end) {
+				done++
+			} else {
+				pending++
+			}
+		}
+
+		chart.Bars = append(chart.Bars, printer.Bar{
+			Label:  p.label(start),
+			Values: []int{pending, done},
+		})
+	}
+
+	chart.Print()
+}
+
+// Draws a chart of the number of tasks added and completed in each period.
+func History(ctx arg_parser.ParseContext) {
+	p := getPeriod(ctx)
+	tasks := chartTasks(ctx)
+	theme := printer.CurrentTheme()
+
+	chart := printer.Chart{
+		Series: []printer.Series{
+			{Name: "Added", Style: theme.ChartPending, Symbol: "+"},
+			{Name: "Completed", Style: theme.ChartDone, Symbol: "X"},
+		},
+	}
+
+	for _, start := range p.starts(time.Now()) {
+		end := p.next(start)
+		added, completed := 0, 0
+
+		for _, task := range tasks {
+			if !task.CreatedAt.Before(start) && task.CreatedAt.Before(end) {
+				added++
+			}
+
+			if completedBefore(task, end) && !completionTime(task).Before(start) {
+				completed++
+			}
+		}
+
+		chart.Bars = append(chart.Bars, printer.Bar{
+			Label:  p.label(start),
+			Values: []int{added, completed},
+		})
+	}
+
+	chart.Print()
+}
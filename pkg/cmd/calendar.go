@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Returns the date of a task used to place it in the calendar, or nil if the
+// task doesn't have the date.
+type dateField func(task storage.Task) *time.Time
+
+var dateFields = map[string]dateField{
+	"due":       func(task storage.Task) *time.Time { return task.Due },
+	"scheduled": func(task storage.Task) *time.Time { return task.Scheduled },
+	"created":   func(task storage.Task) *time.Time { return &task.CreatedAt },
+}
+
+// Returns the date field chosen with the `calendar.date=` config override,
+// defaulting to the due date.
+func getDateField(ctx arg_parser.ParseContext) dateField {
+	field := dateFields["due"]
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.CalendarConfig); ok {
+			if f, ok := dateFields[config.Date]; ok {
+				field = f
+			} else {
+				printer.Error(fmt.Errorf("Invalid calendar date \"%s\", expected one of due, scheduled, or created", config.Date))
+			}
+		}
+	}
+
+	return field
+}
+
+// Parses the month to show, which can be a month in the current year (`jan`
+// or `january`), a specific month (`2024-01`), or relative to the current
+// month (`next` or `prev`). Returns the first day of the month.
+func parseMonth(text string, now time.Time) (time.Time, error) {
+	now = now.Local()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	text = strings.ToLower(text)
+
+	switch text {
+	case "":
+		return current, nil
+	case "next":
+		return current.AddDate(0, 1, 0), nil
+	case "prev":
+		return current.AddDate(0, -1, 0), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", text, time.Local); err == nil {
+		return t, nil
+	}
+
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if text == name || text == name[:3] {
+			return time.Date(now.Year(), month, 1, 0, 0, 0, 0, time.Local), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid month \"%s\"", text)
+}
+
+// Shows a month grid of the tasks matching the filters followed by the list of
+// tasks in the month.
+func Calendar(ctx arg_parser.ParseContext) {
+	month, err := parseMonth(firstTextArg(ctx), time.Now())
+	if err != nil {
+		printer.Error(err)
+	}
+
+	field := getDateField(ctx)
+	format := getFormat(ctx)
+
+	tasks, err := storage.ListTasks(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+	}
+
+	end := month.AddDate(0, 1, 0)
+	counts := make(map[int]int)
+	matches := []storage.Task{}
+
+	for _, task := range tasks {
+		date := field(task)
+		if date == nil || date.Before(month) || !date.Before(end) {
+			continue
+		}
+
+		counts[date.Local().Day()]++
+		matches = append(matches, task)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return field(matches[i]).Before(*field(matches[j]))
+	})
+
+	if format.IsData() {
+		printer.JSON(format, matches)
+		return
+	}
+
+	// The grid is only shown with the table format so other formats only
+	// contain the list of tasks.
+	if format == printer.FormatTable {
+		calendar := printer.Calendar{Month: month, Today: time.Now(), Tasks: counts}
+		calendar.Print()
+
+		if len(matches) == 0 {
+			return
+		}
+
+		fmt.Println()
+	}
+
+	table := printer.Table{
+		Columns:  []string{"ID", "Date", "P", "Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, task := range matches {
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				formatDate(field(task)),
+				task.Priority,
+				strings.Join(task.Tags, " "),
+				task.Title,
+			},
+			Highlight: task.Status == storage.TaskStatusActive,
+			Style:     printer.RuleStyle(task.Priority, task.Tags),
+		})
+	}
+
+	table.PrintAs(format)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Returns the task matching the filters, exiting unless exactly one task
+// matches.
+func singleTask(ctx arg_parser.ParseContext, command string) storage.Task {
+	requireFilters(ctx, command)
+
+	tasks, err := storage.GetTasks(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if len(tasks) == 0 {
+		printer.Error(errors.New("No tasks match filters"))
+	}
+
+	if len(tasks) > 1 {
+		printer.Error(fmt.Errorf("The %s command only supports one task at a time", command))
+	}
+
+	return tasks[0]
+}
+
+// Parses the numbers of checklist items, starting at 1.
+func parseItemNumbers(words []string) ([]int, error) {
+	if len(words) == 0 {
+		return nil, errors.New("Missing checklist item number")
+	}
+
+	var numbers []int
+	for _, word := range words {
+		number, err := strconv.Atoi(word)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid checklist item \"%s\"", word)
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+// Returns true if the task should be completed now that every checklist item
+// is checked, configured with the `checklist.complete=` config override.
+// Defaults to asking, which can be skipped with the `-y` flag.
+func completeChecklist(ctx arg_parser.ParseContext, task storage.Task) bool {
+	complete := "ask"
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.ChecklistConfig); ok {
+			complete = config.Complete
+		}
+	}
+
+	switch complete {
+	case "ask":
+		if _, ok := getFlag(ctx, "yes"); ok {
+			return true
+		}
+
+		return printer.Confirm(fmt.Sprintf("All items are checked, complete task %d?", task.ShortId))
+	case "yes":
+		return true
+	case "no":
+		return false
+	default:
+		printer.Error(fmt.Errorf("Invalid checklist completion \"%s\", expected one of ask, yes, or no", complete))
+		return false
+	}
+}
+
+// Adds, checks, or removes items in the checklist of a task.
+func Check(ctx arg_parser.ParseContext) {
+	task := singleTask(ctx, "check")
+	words := strings.Fields(firstTextArg(ctx))
+
+	if len(words) == 0 {
+		printer.Error(errors.New("Usage: tsk <id> check <number> or tsk <id> check add <text>"))
+	}
+
+	switch words[0] {
+	case "add":
+		text := strings.Join(words[1:], " ")
+		if text == "" {
+			printer.Error(errors.New("Missing checklist item"))
+		}
+
+		task.Checklist = append(task.Checklist, storage.ChecklistItem{Text: text})
+		if err := storage.Update(task); err != nil {
+			printer.Error(err)
+		}
+
+		fmt.Printf("Added item %d to task %d\n", len(task.Checklist), task.ShortId)
+
+	case "remove":
+		numbers, err := parseItemNumbers(words[1:])
+		if err != nil {
+			printer.Error(err)
+		}
+
+		// Items are removed from the end so the numbers of the remaining
+		// items don't change.
+		slices.Sort(numbers)
+		slices.Reverse(numbers)
+		numbers = slices.Compact(numbers)
+
+		for _, number := range numbers {
+			if err := task.RemoveItem(number); err != nil {
+				printer.Error(err)
+			}
+		}
+
+		if err := storage.Update(task); err != nil {
+			printer.Error(err)
+		}
+
+		for _, number := range numbers {
+			fmt.Printf("Removed item %d from task %d\n", number, task.ShortId)
+		}
+
+	default:
+		setChecked(ctx, task, words, true)
+	}
+}
+
+// Unchecks items in the checklist of a task.
+func Uncheck(ctx arg_parser.ParseContext) {
+	task := singleTask(ctx, "uncheck")
+	setChecked(ctx, task, strings.Fields(firstTextArg(ctx)), false)
+}
+
+// Checks or unchecks the checklist items with the given numbers. Checking the
+// last item offers to complete the task.
+func setChecked(ctx arg_parser.ParseContext, task storage.Task, words []string, done bool) {
+	numbers, err := parseItemNumbers(words)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	for _, number := range numbers {
+		if err := task.CheckItem(number, done); err != nil {
+			printer.Error(err)
+		}
+	}
+
+	progress := task.ChecklistProgress()
+	completed := done &&
+		progress.Done == progress.Total &&
+		task.Status != storage.TaskStatusDone &&
+		completeChecklist(ctx, task)
+
+	if completed {
+		task.Status = storage.TaskStatusDone
+	}
+
+	if err := storage.Update(task); err != nil {
+		printer.Error(err)
+	}
+
+	verb := "Checked"
+	if !done {
+		verb = "Unchecked"
+	}
+
+	for _, number := range numbers {
+		fmt.Printf("%s item %d of task %d\n", verb, number, task.ShortId)
+	}
+
+	if completed {
+		fmt.Printf("Completed task %d\n", task.ShortId)
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+//go:embed completions
+var completions embed.FS
+
+func firstTextArg(ctx arg_parser.ParseContext) string {
+	for _, arg := range ctx.Args {
+		if arg, ok := arg.(arg_parser.TextArg); ok {
+			return arg.Text
+		}
+	}
+
+	return ""
+}
+
+// Prints the completion script for a shell.
+func Completion(ctx arg_parser.ParseContext) {
+	shell := firstTextArg(ctx)
+	if shell == "" {
+		printer.Error(errors.New("Missing shell, expected one of bash, zsh, or fish"))
+	}
+
+	script, err := completions.ReadFile("completions/tsk." + shell)
+	if err != nil {
+		printer.Error(fmt.Errorf("Unsupported shell \"%s\", expected one of bash, zsh, or fish", shell))
+	}
+
+	fmt.Print(string(script))
+}
+
+// Prints the values used by the completion scripts, one per line. Values with
+// a description are separated from the description by a tab.
+func Complete(ctx arg_parser.ParseContext) {
+	switch kind := firstTextArg(ctx); kind {
+	case "commands":
+		for _, command := range arg_parser.Commands() {
+			fmt.Println(command)
+		}
+
+	case "scopes":
+		for _, scope := range arg_parser.Scopes() {
+			fmt.Printf("%s:\n", scope)
+		}
+
+	case "tags":
+		tags, err := storage.ListTags()
+		if err != nil {
+			printer.Error(err)
+		}
+
+		for _, tag := range tags {
+			fmt.Println(tag)
+		}
+
+	case "ids":
+		tasks, err := storage.ListTasks(nil)
+		if err != nil {
+			printer.Error(err)
+		}
+
+		for _, task := range tasks {
+			fmt.Printf("%d\t%s\n", task.ShortId, task.Title)
+		}
+
+	default:
+		printer.Error(fmt.Errorf("Invalid completion \"%s\"", kind))
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Runs `tsk _complete <kind>` and returns the printed lines.
+func complete(t *testing.T, kind string) []string {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	parser := arg_parser.New()
+	Complete(parser.Parse([]string{"_complete", kind}))
+
+	assert.NoError(t, w.Close())
+	output, err := io.ReadAll(r)
+	assert.NoError(t, err)
+
+	return strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+}
+
+func TestComplete(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	for _, task := range []struct {
+		title string
+		tags  []string
+	}{
+		{"Buy milk", []string{"home"}},
+		{"Deploy", []string{"work"}},
+		{"Pay bills", []string{"home", "bills"}},
+		{"Old task", []string{"trashed"}},
+	} {
+		added := storage.NewTask()
+		added.Title = task.title
+		added.Tags = task.tags
+
+		_, err := storage.Add(added)
+		assert.NoError(t, err)
+	}
+
+	_, err := storage.Edit(
+		[]sql_builder.Filter{shortIdsFilter([]int{2})},
+		[]storage.QueryEdit{{Path: "status", Value: string(storage.TaskStatusDone)}},
+	)
+	assert.NoError(t, err)
+
+	_, err = storage.Delete([]sql_builder.Filter{shortIdsFilter([]int{4})})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"bills", "home"}, complete(t, "tags"))
+	assert.ElementsMatch(t, []string{"1\tBuy milk", "3\tPay bills"}, complete(t, "ids"))
+	assert.Contains(t, complete(t, "commands"), "add")
+	assert.Contains(t, complete(t, "scopes"), "priority.above:")
+}
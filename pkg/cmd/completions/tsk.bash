@@ -0,0 +1,29 @@
+# bash completion for tsk
+
+_tsk() {
+	local cur="${COMP_WORDS[COMP_CWORD]}"
+	local IFS=$'\n'
+
+	case "$cur" in
+	+* | -*)
+		# Complete existing tags after `+` or `-`
+		local tags
+		tags="$(tsk _complete tags 2>/dev/null)"
+		COMPREPLY=($(compgen -P "${cur:0:1}" -W "$tags" -- "${cur:1}"))
+		;;
+	*)
+		local words
+		words="$(tsk _complete commands 2>/dev/null)
+$(tsk _complete scopes 2>/dev/null)
+$(tsk _complete ids 2>/dev/null | cut -f1)"
+		COMPREPLY=($(compgen -W "$words" -- "$cur"))
+
+		# Don't add a space after scopes such as `priority:`
+		if [[ ${#COMPREPLY[@]} -eq 1 && ${COMPREPLY[0]} == *: ]]; then
+			compopt -o nospace
+		fi
+		;;
+	esac
+}
+
+complete -F _tsk tsk
@@ -0,0 +1,19 @@
+# fish completion for tsk
+
+function __tsk_complete
+    tsk _complete $argv 2>/dev/null
+end
+
+function __tsk_tag_prefix
+    string match -qr '^[+-]' -- (commandline -ct)
+end
+
+complete -c tsk -f
+
+complete -c tsk -n 'not __tsk_tag_prefix' -a '(__tsk_complete commands)'
+complete -c tsk -n 'not __tsk_tag_prefix' -a '(__tsk_complete scopes)'
+complete -c tsk -n 'not __tsk_tag_prefix' -a '(__tsk_complete ids)'
+
+# Complete existing tags after `+` or `-`
+complete -c tsk -n '__tsk_tag_prefix' -a '(__tsk_complete tags | string replace -r "^" "+")'
+complete -c tsk -n '__tsk_tag_prefix' -a '(__tsk_complete tags | string replace -r "^" "-")'
@@ -3,43 +3,68 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
 	"github.com/mskelton/tsk/internal/printer"
 	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
 )
 
+// Moves the tasks matching the filters to the trash after showing them and
+// asking for confirmation.
 func Delete(ctx arg_parser.ParseContext) {
 	requireFilters(ctx, "delete")
 
 	filters := buildFilters(ctx)
-	count, err := storage.Count(filters)
+	tasks, err := storage.GetTasks(filters)
 	if err != nil {
 		printer.Error(err)
-		return
 	}
 
-	if count == 0 {
+	if len(tasks) == 0 {
 		printer.Error(errors.New("No tasks match filters"))
-		return
 	}
 
-	if count != 1 {
-		printer.Error(errors.New("Bulk delete is not supported"))
-		return
-	}
+	previewTasks(ctx, tasks)
+
+	count := len(tasks)
+	fmt.Printf("\nThis command will move %d %s to the trash\n", count, utils.Pluralize(count, "task", "tasks"))
 
-	if !printer.Confirm("Are you sure you want to continue?") {
+	if !confirm(ctx) {
 		return
 	}
 
 	ids, err := storage.Delete(filters)
 	if err != nil {
 		printer.Error(err)
-		return
 	}
 
 	for _, id := range ids {
 		fmt.Printf("Deleted task %d\n", id)
 	}
 }
+
+// Prints the tasks affected by a command before asking for confirmation.
+func previewTasks(ctx arg_parser.ParseContext, tasks []storage.Task) {
+	table := printer.Table{
+		Columns:  []string{"ID", "P", "Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, task := range tasks {
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				task.Priority,
+				strings.Join(task.Tags, " "),
+				task.Title,
+			},
+			Style: printer.RuleStyle(task.Priority, task.Tags),
+		})
+	}
+
+	table.Print()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mskelton/tsk/internal/arg_parser"
 	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
 	"github.com/mskelton/tsk/internal/storage"
 	"github.com/mskelton/tsk/internal/utils"
 )
@@ -25,13 +26,41 @@ func Done(ctx arg_parser.ParseContext) {
 		return
 	}
 
+	subtasks, err := storage.OpenSubtasks(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	// With `--cascade`, the open subtasks are completed along with their
+	// parents.
+	_, cascade := getFlag(ctx, "cascade")
+	if cascade && len(subtasks) > 0 {
+		tasks, err := storage.GetTasks(filters)
+		if err != nil {
+			printer.Error(err)
+		}
+
+		filters = []sql_builder.Filter{storage.TaskIdsFilter(append(tasks, subtasks...))}
+		count += len(subtasks)
+	}
+
 	fmt.Printf(
 		"This command will complete %d %s\n",
 		count,
 		utils.Pluralize(count, "task", "tasks"),
 	)
 
-	if utils.IsBulk(ctx, count) && !printer.Confirm("Are you sure you want to continue?") {
+	if len(subtasks) > 0 && !cascade {
+		fmt.Printf(
+			"%d open %s will not be completed, use --cascade to complete them too\n",
+			len(subtasks),
+			utils.Pluralize(len(subtasks), "subtask", "subtasks"),
+		)
+
+		if !confirm(ctx) {
+			return
+		}
+	} else if utils.IsBulk(ctx, count) && !confirm(ctx) {
 		return
 	}
 
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Creates a copy of each task matching the filters with the args applied on
+// top, such as a new title, tags, or priority.
+func Duplicate(ctx arg_parser.ParseContext) {
+	requireFilters(ctx, "duplicate")
+
+	tasks, err := storage.GetTasks(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+	}
+
+	count := len(tasks)
+	if count == 0 {
+		printer.Error(errors.New("No tasks match filters"))
+	}
+
+	if utils.IsBulk(ctx, count) {
+		fmt.Printf("This command will duplicate %d tasks\n", count)
+
+		if !confirm(ctx) {
+			return
+		}
+	}
+
+	for _, task := range tasks {
+		duplicate := task.Duplicate()
+		applyArgs(&duplicate, ctx)
+
+		if duplicate.Title == "" {
+			printer.Error(errors.New("Missing title"))
+		}
+
+		id, err := storage.Add(duplicate)
+		if err != nil {
+			printer.Error(err)
+		}
+
+		fmt.Printf("Created task %d from task %d\n", id, task.ShortId)
+	}
+}
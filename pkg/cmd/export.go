@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/ical"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/todotxt"
+)
+
+// Exports the tasks matching the filters, including completed tasks, as JSON
+// or in another format with the `--format` flag.
+func Export(ctx arg_parser.ParseContext) {
+	format, ok := getFlag(ctx, "format")
+	if !ok {
+		format = "json"
+	}
+
+	tasks, err := storage.GetTasks(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+		return
+	}
+
+	switch format {
+	case "json":
+		printer.JSON(printer.FormatJSON, tasks)
+	case "ics":
+		if err := ical.Write(os.Stdout, tasks); err != nil {
+			printer.Error(fmt.Errorf("Failed to export tasks: %w", err))
+		}
+	case "todotxt":
+		if err := todotxt.Write(os.Stdout, tasks); err != nil {
+			printer.Error(fmt.Errorf("Failed to export tasks: %w", err))
+		}
+	default:
+		printer.Error(fmt.Errorf("Invalid export format \"%s\", expected one of json, ics, or todotxt", format))
+	}
+}
@@ -17,7 +17,30 @@ Commands:
   start         Start a task
   stop          Stop a task
   get           Get a task
-  delete        Delete a task
+  duplicate     Copy tasks with changes
+  subtask       Add a subtask to a task
+  check         Add, check, or remove checklist items
+  uncheck       Uncheck checklist items
+  delete        Move tasks to the trash
+  trash         Show deleted tasks
+  restore       Restore deleted tasks
+  purge         Permanently delete tasks in the trash
+  archive       Move completed tasks to the archive
+  search        Search tasks by relevance
+  ui            Open the interactive task list
+  tags          Show all tags and the number of tasks using them
+  tag           Rename, merge, or delete a tag
+  blueprint     Save, list, or delete task blueprints
+  sync          Sync tasks with other devices
+  serve         Start the HTTP API server
+  export        Export tasks to JSON, iCalendar, or todo.txt
+  import        Import tasks from JSON, iCalendar, or todo.txt
+  waiting       Show tasks that are hidden until a later date
+  stats         Show statistics about your tasks
+  burndown      Chart pending and completed tasks over time
+  history       Chart tasks added and completed over time
+  calendar      Show a calendar of tasks by date
+  completion    Print the shell completion script
   help          Show this help message
   version       Show the version
 
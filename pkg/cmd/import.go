@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/ical"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/todotxt"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Returns the format of the file to import, detected from the file extension
+// unless it was set with the `--format` flag.
+func importFormat(path string, format string) string {
+	if format != "" {
+		return format
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical":
+		return "ics"
+	case ".txt":
+		return "todotxt"
+	default:
+		return "json"
+	}
+}
+
+// Reads the tasks from a JSON, iCalendar, or todo.txt file in the format.
+func readImport(path string, format string) ([]storage.Task, error) {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to import tasks: %w", err)
+		}
+
+		defer file.Close()
+		r = file
+	}
+
+	switch format {
+	case "json":
+		var tasks []storage.Task
+		if err := json.NewDecoder(r).Decode(&tasks); err != nil {
+			return nil, fmt.Errorf("Failed to import tasks: %w", err)
+		}
+
+		return tasks, nil
+	case "ics":
+		tasks, err := ical.Read(r)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to import tasks: %w", err)
+		}
+
+		return tasks, nil
+	case "todotxt":
+		return todotxt.Read(r)
+	default:
+		return nil, fmt.Errorf("Invalid import format \"%s\", expected one of json, ics, or todotxt", format)
+	}
+}
+
+// Returns the time to use for a date of an imported task. Some formats only
+// include the date, so the existing time is kept if it is on the same day.
+func importedDate(existing *time.Time, imported *time.Time) *time.Time {
+	if existing != nil && imported != nil && existing.Local().Format(time.DateOnly) == imported.Local().Format(time.DateOnly) {
+		return existing
+	}
+
+	return imported
+}
+
+// Copies the imported data to an existing task, returning true if any of the
+// data changed. Tasks in the trash are restored by the update. Only JSON
+// includes the checklist and parent of tasks, so they are kept when importing
+// other formats.
+func merge(existing *storage.Task, imported storage.Task, format string) bool {
+	updated := *existing
+	updated.Title = imported.Title
+	updated.Status = imported.Status
+	updated.Priority = imported.Priority
+	updated.Tags = imported.Tags
+	updated.Project = imported.Project
+	updated.Attributes = imported.Attributes
+	updated.Due = importedDate(existing.Due, imported.Due)
+	updated.Scheduled = importedDate(existing.Scheduled, imported.Scheduled)
+	updated.Wait = importedDate(existing.Wait, imported.Wait)
+	updated.CompletedAt = importedDate(existing.CompletedAt, imported.CompletedAt)
+	updated.DeletedAt = nil
+
+	if format == "json" {
+		updated.Parent = imported.Parent
+		updated.Checklist = imported.Checklist
+	}
+
+	if reflect.DeepEqual(*existing, updated) {
+		return false
+	}
+
+	*existing = updated
+	return true
+}
+
+// Finds the existing task matching an imported task by its unique id, or by
+// its title for formats that don't include ids. Tasks in the trash are matched
+// by id so the imported task updates them rather than conflicting with their
+// id. A title matching more than one task is an error, since any of them could
+// be the imported task.
+func findExisting(task storage.Task) ([]storage.Task, error) {
+	if task.Id != "" {
+		filters := []sql_builder.Filter{taskFilter(task.Id)}
+
+		tasks, err := storage.GetTasks(filters)
+		if err != nil || len(tasks) > 0 {
+			return tasks, err
+		}
+
+		return storage.ListTrash(filters)
+	}
+
+	tasks, err := storage.GetTasks([]sql_builder.Filter{{
+		Key:      "data ->> 'title'",
+		Operator: sql_builder.Eq,
+		Value:    quote(task.Title),
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) > 1 {
+		return nil, fmt.Errorf("Failed to import tasks: %d tasks have the title \"%s\", add an id to the imported task to choose one", len(tasks), task.Title)
+	}
+
+	return tasks, nil
+}
+
+// Imports tasks from a file, matching them to existing tasks by their unique
+// id. Importing the same file more than once has no effect, and existing tasks
+// are only updated if the imported task was modified more recently. Tasks
+// imported from formats without modification times always replace the
+// existing task.
+func Import(ctx arg_parser.ParseContext) {
+	path := firstTextArg(ctx)
+	if path == "" {
+		printer.Error(errors.New("Missing file to import"))
+		return
+	}
+
+	format, _ := getFlag(ctx, "format")
+	format = importFormat(path, format)
+
+	tasks, err := readImport(path, format)
+	if err != nil {
+		printer.Error(err)
+		return
+	}
+
+	added, updated, skipped := 0, 0, 0
+
+	for _, task := range tasks {
+		if task.Title == "" {
+			printer.Error(errors.New("Failed to import tasks: every task must have a title"))
+			return
+		}
+
+		if task.Tags == nil {
+			task.Tags = make([]string, 0)
+		}
+
+		// Ids from other apps can contain any characters, so they are mapped to
+		// ids that are safe to use in queries.
+		task.Id = utils.NormalizeId(task.Id)
+		task.Parent = utils.NormalizeId(task.Parent)
+
+		task.Priority, err = storage.ParsePriority(task.Priority)
+		if err != nil {
+			printer.Error(fmt.Errorf("Failed to import tasks: %w", err))
+			return
+		}
+
+		existing, err := findExisting(task)
+		if err != nil {
+			printer.Error(err)
+			return
+		}
+
+		if len(existing) == 0 {
+			now := time.Now()
+			if task.Id == "" {
+				task.Id = utils.GenerateId()
+			}
+
+			if task.CreatedAt.IsZero() {
+				task.CreatedAt = now
+			}
+
+			if task.UpdatedAt.IsZero() {
+				task.UpdatedAt = now
+			}
+
+			task.ShortId = 0
+			task.TemplateId = ""
+
+			if _, err := storage.Add(task); err != nil {
+				printer.Error(err)
+				return
+			}
+
+			added++
+			continue
+		}
+
+		// iCalendar times only have second precision, so the local time is
+		// truncated before comparing.
+		current := existing[0]
+		if !task.UpdatedAt.IsZero() && !task.UpdatedAt.After(current.UpdatedAt.Truncate(time.Second)) {
+			skipped++
+			continue
+		}
+
+		if !merge(&current, task, format) {
+			skipped++
+			continue
+		}
+
+		if err := storage.Update(current); err != nil {
+			printer.Error(err)
+			return
+		}
+
+		updated++
+	}
+
+	fmt.Printf("Imported %d tasks (%d added, %d updated, %d unchanged)\n", len(tasks), added, updated, skipped)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/mskelton/tsk/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func importTasks(t *testing.T, tasks []storage.Task) {
+	data, err := json.Marshal(tasks)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+
+	Import(arg_parser.ParseContext{
+		Command: arg_parser.Import,
+		Args:    []arg_parser.Arg{arg_parser.TextArg{Text: path}},
+	})
+}
+
+func TestImportKeepsTrashedTasks(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Buy milk")
+
+	exported, err := storage.GetTasks(nil)
+	assert.NoError(t, err)
+
+	_, err = storage.Delete([]sql_builder.Filter{shortIdsFilter([]int{1})})
+	assert.NoError(t, err)
+
+	// An older export doesn't bring back the deleted task
+	exported[0].UpdatedAt = time.Now().Add(-time.Hour)
+	importTasks(t, exported)
+
+	tasks, err := storage.GetTasks(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	// A newer version of the task restores it
+	exported[0].Title = "Buy oat milk"
+	exported[0].UpdatedAt = time.Now().Add(time.Minute)
+	importTasks(t, exported)
+
+	tasks, err = storage.GetTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "Buy oat milk", tasks[0].Title)
+	assert.Nil(t, tasks[0].DeletedAt)
+}
+
+func TestImportNormalizesPriorities(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	task := storage.NewTask()
+	task.Title = "Buy milk"
+	task.Priority = "h"
+	importTasks(t, []storage.Task{task})
+
+	tasks, err := storage.GetTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "H", tasks[0].Priority)
+}
+
+func TestImportNormalizesIds(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		"UID:it's-1",
+		"SUMMARY:Buy milk",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	path := filepath.Join(t.TempDir(), "tasks.ics")
+	assert.NoError(t, os.WriteFile(path, []byte(calendar), 0o644))
+
+	ctx := arg_parser.ParseContext{
+		Command: arg_parser.Import,
+		Args:    []arg_parser.Arg{arg_parser.TextArg{Text: path}},
+	}
+
+	Import(ctx)
+	Import(ctx)
+
+	tasks, err := storage.GetTasks(nil)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, utils.NormalizeId("it's-1"), tasks[0].Id)
+
+	filters := []sql_builder.Filter{shortIdsFilter([]int{tasks[0].ShortId})}
+	_, err = storage.Edit(filters, []storage.QueryEdit{{Path: "status", Value: "done"}})
+	assert.NoError(t, err)
+
+	_, err = storage.Delete(filters)
+	assert.NoError(t, err)
+
+	ids, err := storage.Purge(nil, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{tasks[0].ShortId}, ids)
+}
+
+func TestImportUpdatesChecklistAndParent(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Release", "Deploy")
+
+	exported, err := storage.GetTasks(nil)
+	assert.NoError(t, err)
+
+	exported[1].Parent = exported[0].Id
+	exported[1].Checklist = []storage.ChecklistItem{{Text: "Tag release", Done: true}, {Text: "Publish"}}
+	exported[1].UpdatedAt = time.Now().Add(time.Minute)
+	importTasks(t, exported)
+
+	tasks, err := storage.GetTasks([]sql_builder.Filter{shortIdsFilter([]int{2})})
+	assert.NoError(t, err)
+	assert.Equal(t, exported[0].Id, tasks[0].Parent)
+	assert.Equal(t, exported[1].Checklist, tasks[0].Checklist)
+
+	// Removing them from the imported task removes them from the existing task
+	exported[1].Parent = ""
+	exported[1].Checklist = nil
+	exported[1].UpdatedAt = time.Now().Add(2 * time.Minute)
+	importTasks(t, exported)
+
+	tasks, err = storage.GetTasks([]sql_builder.Filter{shortIdsFilter([]int{2})})
+	assert.NoError(t, err)
+	assert.Empty(t, tasks[0].Parent)
+	assert.Empty(t, tasks[0].Checklist)
+
+	// Formats without checklists keep the checklist of the existing task
+	checklist := []storage.ChecklistItem{{Text: "Publish"}}
+	exported[1].Checklist = checklist
+	exported[1].UpdatedAt = time.Now().Add(3 * time.Minute)
+	importTasks(t, exported)
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO",
+		"UID:" + exported[1].Id,
+		"SUMMARY:Deploy v2",
+		"LAST-MODIFIED:" + time.Now().Add(4*time.Minute).UTC().Format("20060102T150405Z"),
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	path := filepath.Join(t.TempDir(), "tasks.ics")
+	assert.NoError(t, os.WriteFile(path, []byte(calendar), 0o644))
+
+	Import(arg_parser.ParseContext{
+		Command: arg_parser.Import,
+		Args:    []arg_parser.Arg{arg_parser.TextArg{Text: path}},
+	})
+
+	tasks, err = storage.GetTasks([]sql_builder.Filter{shortIdsFilter([]int{2})})
+	assert.NoError(t, err)
+	assert.Equal(t, "Deploy v2", tasks[0].Title)
+	assert.Equal(t, checklist, tasks[0].Checklist)
+}
+
+func TestFindExistingByTitle(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Buy milk", "Walk dog", "Walk dog")
+
+	existing, err := findExisting(storage.Task{Title: "Buy milk"})
+	assert.NoError(t, err)
+	assert.Len(t, existing, 1)
+
+	existing, err = findExisting(storage.Task{Title: "Buy eggs"})
+	assert.NoError(t, err)
+	assert.Empty(t, existing)
+
+	_, err = findExisting(storage.Task{Title: "Walk dog"})
+	assert.EqualError(t, err, "Failed to import tasks: 2 tasks have the title \"Walk dog\", add an id to the imported task to choose one")
+}
@@ -12,25 +12,37 @@ import (
 )
 
 func List(ctx arg_parser.ParseContext) {
+	format := getFormat(ctx)
 	filters := buildFilters(ctx)
 	tasks, err := storage.ListTasks(filters)
 	if err != nil {
 		printer.Error(err)
 	}
 
-	if len(tasks) == 0 {
+	if format.IsData() {
+		printer.JSON(format, tasks)
+		return
+	}
+
+	if len(tasks) == 0 && format == printer.FormatTable {
 		printer.Message("No tasks match filters")
 		return
 	}
 
+	progress, err := storage.SubtaskProgress()
+	if err != nil {
+		printer.Error(err)
+	}
+
 	table := printer.Table{
-		Columns: []string{"ID", "Active", "Age", "P", "Tags", "Title"},
-		Rows:    []printer.Row{},
+		Columns:  []string{"ID", "Active", "Age", "P", "Project", "Due", "Tags", "Checklist", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
 	}
 
-	for _, task := range tasks {
+	for _, task := range taskTree(tasks) {
 		var status string
-		if task.Status == storage.TaskStatusActive && color.NoColor {
+		if task.Status == storage.TaskStatusActive && (color.NoColor || format != printer.FormatTable) {
 			status = "✔︎"
 		}
 
@@ -40,12 +52,16 @@ func List(ctx arg_parser.ParseContext) {
 				status,
 				utils.ShortDuration(task.CreatedAt),
 				task.Priority,
+				task.Project,
+				formatDate(task.Due),
 				strings.Join(task.Tags, " "),
-				task.Title,
+				checklistProgress(task.Task),
+				treeTitle(task, progress, format),
 			},
 			Highlight: task.Status == storage.TaskStatusActive,
+			Style:     printer.RuleStyle(task.Priority, task.Tags),
 		})
 	}
 
-	table.Print()
+	table.PrintAs(format)
 }
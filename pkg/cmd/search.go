@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Searches the tasks matching the filters, most relevant first. Completed tasks
+// are only included with the `--all` flag.
+func Search(ctx arg_parser.ParseContext) {
+	var words []string
+	for _, arg := range ctx.Args {
+		if arg, ok := arg.(arg_parser.TextArg); ok {
+			words = append(words, arg.Text)
+		}
+	}
+
+	query := strings.Join(words, " ")
+	if query == "" {
+		printer.Error(errors.New("Missing search query"))
+	}
+
+	filters := buildFilters(ctx)
+	if _, all := getFlag(ctx, "all"); !all {
+		filters = append(filters, sql_builder.Filter{
+			Key:      "tasks.data ->> '$.status'",
+			Operator: sql_builder.Neq,
+			Value:    "'done'",
+		})
+	}
+
+	// Matches are only highlighted in tables since other formats are meant to
+	// be read by other tools.
+	format := getFormat(ctx)
+	start, end := "", ""
+	if format == printer.FormatTable {
+		start, end = printer.HighlightStart, printer.HighlightEnd
+	}
+
+	results, err := storage.Search(query, filters, start, end)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if format.IsData() {
+		tasks := make([]storage.Task, len(results))
+		for i, result := range results {
+			tasks[i] = result.Task
+		}
+
+		printer.JSON(format, tasks)
+		return
+	}
+
+	if len(results) == 0 && format == printer.FormatTable {
+		printer.Message("No tasks match search")
+		return
+	}
+
+	table := printer.Table{
+		Columns:  []string{"ID", "P", "Project", "Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, result := range results {
+		task := result.Task
+
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				task.Priority,
+				task.Project,
+				strings.Join(task.Tags, " "),
+				result.Title,
+			},
+			Highlight: task.Status == storage.TaskStatusActive,
+			Style:     printer.RuleStyle(task.Priority, task.Tags),
+		})
+	}
+
+	table.PrintAs(format)
+}
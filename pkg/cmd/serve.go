@@ -0,0 +1,495 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+const defaultAddr = "127.0.0.1:7777"
+
+type server struct {
+	// The address the server listens on
+	addr  string
+	token string
+	// Writes are serialized so the ETag check and the write happen atomically
+	// with respect to other API requests.
+	mu sync.Mutex
+}
+
+// The fields of a task that can be set when creating or patching a task.
+// Fields that are omitted from the request body are left unchanged.
+type taskInput struct {
+	Title    *string             `json:"title"`
+	Priority *string             `json:"priority"`
+	Status   *storage.TaskStatus `json:"status"`
+	Tags     *[]string           `json:"tags"`
+	// The `updated_at` time of the task the client last saw, which can be used
+	// instead of the `If-Match` header to detect conflicting writes.
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// An error returned by a handler along with the HTTP status to respond with.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e apiError) Error() string {
+	return e.message
+}
+
+func newAPIError(status int, format string, args ...any) apiError {
+	return apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// Returns the ETag of a task, which changes every time the task is updated.
+func etag(task storage.Task) string {
+	return fmt.Sprintf(`"%s"`, strconv.FormatInt(task.UpdatedAt.UnixNano(), 36))
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		status = apiErr.status
+	}
+
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeTask(w http.ResponseWriter, status int, task storage.Task) {
+	w.Header().Set("ETag", etag(task))
+	writeJSON(w, status, task)
+}
+
+// Parses a filter query using the same syntax as the command line.
+func parseQuery(query string) ([]sql_builder.Filter, error) {
+	parser := arg_parser.New()
+	ctx := parser.Parse(append([]string{"list"}, strings.Fields(query)...))
+
+	filters, err := parseFilters(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, "%s", err)
+	}
+
+	return filters, nil
+}
+
+func getTask(id int) (storage.Task, error) {
+	filters := buildFilters(arg_parser.ParseContext{
+		Filters: []arg_parser.Filter{arg_parser.IdFilter{Ids: []int{id}}},
+	})
+
+	tasks, err := storage.GetTasks(filters)
+	if err != nil {
+		return storage.Task{}, err
+	}
+
+	if len(tasks) == 0 {
+		return storage.Task{}, newAPIError(http.StatusNotFound, "Task %d not found", id)
+	}
+
+	return tasks[0], nil
+}
+
+// Checks that the client has seen the latest version of the task, using the
+// `If-Match` header or the `updated_at` field of the request body. Requests
+// without either are always allowed.
+func checkVersion(r *http.Request, task storage.Task, updatedAt *time.Time) error {
+	if match := r.Header.Get("If-Match"); match != "" && match != "*" {
+		tags := strings.Split(match, ",")
+		for i, tag := range tags {
+			tags[i] = strings.TrimSpace(tag)
+		}
+
+		if !slices.Contains(tags, etag(task)) {
+			return newAPIError(http.StatusPreconditionFailed, "Task %d was modified by another client", task.ShortId)
+		}
+	}
+
+	if updatedAt != nil && !updatedAt.Equal(task.UpdatedAt) {
+		return newAPIError(http.StatusPreconditionFailed, "Task %d was modified by another client", task.ShortId)
+	}
+
+	return nil
+}
+
+func decodeInput(r *http.Request) (taskInput, error) {
+	var input taskInput
+
+	// Browsers can send cross-origin form and text requests without a
+	// preflight, so only JSON bodies are accepted.
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return input, newAPIError(http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return input, newAPIError(http.StatusBadRequest, "Invalid request body: %s", err)
+	}
+
+	return input, nil
+}
+
+// Applies the fields of the input to the task.
+func (input taskInput) apply(task *storage.Task) error {
+	if input.Title != nil {
+		task.Title = *input.Title
+	}
+
+	if input.Priority != nil {
+		priority, err := storage.ParsePriority(*input.Priority)
+		if err != nil {
+			return newAPIError(http.StatusBadRequest, "%s", err)
+		}
+
+		task.Priority = priority
+	}
+
+	if input.Tags != nil {
+		task.Tags = *input.Tags
+	}
+
+	if input.Status != nil {
+		switch *input.Status {
+		case storage.TaskStatusPending, storage.TaskStatusActive, storage.TaskStatusDone:
+			task.Status = *input.Status
+		default:
+			return newAPIError(http.StatusBadRequest, "Invalid status \"%s\"", *input.Status)
+		}
+	}
+
+	if task.Title == "" {
+		return newAPIError(http.StatusBadRequest, "Missing title")
+	}
+
+	if task.Tags == nil {
+		task.Tags = make([]string, 0)
+	}
+
+	return nil
+}
+
+// GET /tasks?filter=...
+func (s *server) listTasks(w http.ResponseWriter, r *http.Request) error {
+	filters, err := parseQuery(r.URL.Query().Get("filter"))
+	if err != nil {
+		return err
+	}
+
+	var tasks []storage.Task
+
+	if all, _ := strconv.ParseBool(r.URL.Query().Get("all")); all {
+		tasks, err = storage.GetTasks(filters)
+	} else {
+		tasks, err = storage.ListTasks(filters)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if tasks == nil {
+		tasks = []storage.Task{}
+	}
+
+	writeJSON(w, http.StatusOK, tasks)
+	return nil
+}
+
+// POST /tasks
+func (s *server) createTask(w http.ResponseWriter, r *http.Request) error {
+	input, err := decodeInput(r)
+	if err != nil {
+		return err
+	}
+
+	task := storage.NewTask()
+	if err := input.apply(&task); err != nil {
+		return err
+	}
+
+	id, err := storage.Add(task)
+	if err != nil {
+		return err
+	}
+
+	task, err = getTask(int(id))
+	if err != nil {
+		return err
+	}
+
+	writeTask(w, http.StatusCreated, task)
+	return nil
+}
+
+// GET /tasks/{id}
+func (s *server) showTask(w http.ResponseWriter, r *http.Request, id int) error {
+	task, err := getTask(id)
+	if err != nil {
+		return err
+	}
+
+	if r.Header.Get("If-None-Match") == etag(task) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	writeTask(w, http.StatusOK, task)
+	return nil
+}
+
+// PATCH /tasks/{id}
+func (s *server) patchTask(w http.ResponseWriter, r *http.Request, id int) error {
+	input, err := decodeInput(r)
+	if err != nil {
+		return err
+	}
+
+	task, err := getTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(r, task, input.UpdatedAt); err != nil {
+		return err
+	}
+
+	if err := input.apply(&task); err != nil {
+		return err
+	}
+
+	if err := storage.Update(task); err != nil {
+		return err
+	}
+
+	task, err = getTask(id)
+	if err != nil {
+		return err
+	}
+
+	writeTask(w, http.StatusOK, task)
+	return nil
+}
+
+// POST /tasks/{id}/done, /tasks/{id}/start, and /tasks/{id}/stop
+func (s *server) setStatus(w http.ResponseWriter, r *http.Request, id int, status storage.TaskStatus) error {
+	task, err := getTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(r, task, nil); err != nil {
+		return err
+	}
+
+	edits := []storage.QueryEdit{{Path: "status", Value: string(status)}}
+	if _, err := storage.Edit([]sql_builder.Filter{taskFilter(task.Id)}, edits); err != nil {
+		return err
+	}
+
+	task, err = getTask(id)
+	if err != nil {
+		return err
+	}
+
+	writeTask(w, http.StatusOK, task)
+	return nil
+}
+
+// DELETE /tasks/{id}
+func (s *server) deleteTask(w http.ResponseWriter, r *http.Request, id int) error {
+	task, err := getTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(r, task, nil); err != nil {
+		return err
+	}
+
+	if _, err := storage.Delete([]sql_builder.Filter{taskFilter(task.Id)}); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Returns true if the request wasn't sent by a web page on another origin.
+// Browsers include the `Origin` header in every write request, while other
+// clients such as curl don't send it at all.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// Returns true if the `Host` of the request is the address the server listens
+// on or a loopback address. Checking the host prevents DNS rebinding, where a
+// web page on another domain resolves its own name to the server so its
+// requests are treated as same-origin.
+func (s *server) allowedHost(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	host = strings.Trim(host, "[]")
+	if host == "localhost" {
+		return true
+	}
+
+	listen, _, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		listen = s.addr
+	}
+
+	if strings.EqualFold(host, listen) {
+		return true
+	}
+
+	// A server listening on every interface can be reached by any of the IP
+	// addresses of the machine, which can't be used for DNS rebinding.
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	listenIP := net.ParseIP(listen)
+	return ip.IsLoopback() || listen == "" || (listenIP != nil && listenIP.IsUnspecified())
+}
+
+// Routes a request to its handler.
+func (s *server) route(w http.ResponseWriter, r *http.Request) error {
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	if parts[0] != "tasks" {
+		return newAPIError(http.StatusNotFound, "Not found")
+	}
+
+	// Reads are not serialized since they don't need to be consistent with
+	// the ETag checks of other requests.
+	if r.Method != http.MethodGet {
+		if !sameOrigin(r) {
+			return newAPIError(http.StatusForbidden, "Cross-origin requests are not allowed")
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listTasks(w, r)
+		case http.MethodPost:
+			return s.createTask(w, r)
+		default:
+			return newAPIError(http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return newAPIError(http.StatusNotFound, "Invalid task id \"%s\"", parts[1])
+	}
+
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.showTask(w, r, id)
+		case http.MethodPatch:
+			return s.patchTask(w, r, id)
+		case http.MethodDelete:
+			return s.deleteTask(w, r, id)
+		default:
+			return newAPIError(http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	}
+
+	if len(parts) == 3 && r.Method == http.MethodPost {
+		switch parts[2] {
+		case "done":
+			return s.setStatus(w, r, id, storage.TaskStatusDone)
+		case "start":
+			return s.setStatus(w, r, id, storage.TaskStatusActive)
+		case "stop":
+			return s.setStatus(w, r, id, storage.TaskStatusPending)
+		}
+	}
+
+	return newAPIError(http.StatusNotFound, "Not found")
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedHost(r) {
+		writeError(w, newAPIError(http.StatusForbidden, "Invalid host \"%s\"", r.Host))
+		return
+	}
+
+	if s.token != "" {
+		auth := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte("Bearer "+s.token)) != 1 {
+			writeError(w, newAPIError(http.StatusUnauthorized, "Unauthorized"))
+			return
+		}
+	}
+
+	if err := s.route(w, r); err != nil {
+		writeError(w, err)
+	}
+}
+
+// Starts a local HTTP server with a JSON API for reading and writing tasks.
+func Serve(ctx arg_parser.ParseContext) {
+	addr, ok := getFlag(ctx, "addr")
+	if !ok {
+		addr = defaultAddr
+	} else if addr == "" {
+		printer.Error(errors.New("Missing value for \"--addr\""))
+	}
+
+	// The token can be set with an environment variable to avoid exposing it
+	// in the process list.
+	token, ok := getFlag(ctx, "token")
+	if !ok {
+		token = os.Getenv("TSK_API_TOKEN")
+	} else if token == "" {
+		printer.Error(errors.New("Missing value for \"--token\""))
+	}
+
+	fmt.Printf("Listening on http://%s\n", addr)
+
+	if err := http.ListenAndServe(addr, &server{addr: addr, token: token}); err != nil {
+		printer.Error(fmt.Errorf("Failed to start server: %w", err))
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveRequest(method string, path string, body string, headers map[string]string) int {
+	return serveHost(defaultAddr, "127.0.0.1:7777", method, path, body, headers)
+}
+
+// Sends a request to a server listening on the address, with the host as the
+// `Host` header of the request.
+func serveHost(addr string, host string, method string, path string, body string, headers map[string]string) int {
+	r := httptest.NewRequest(method, "http://"+host+path, strings.NewReader(body))
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+
+	w := httptest.NewRecorder()
+	(&server{addr: addr}).ServeHTTP(w, r)
+
+	return w.Code
+}
+
+func TestServeRejectsCrossOriginWrites(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Buy milk")
+
+	body := `{"title": "Buy eggs"}`
+	json := map[string]string{"Content-Type": "application/json"}
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveRequest("POST", "/tasks", body, nil))
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveRequest("POST", "/tasks", body, map[string]string{"Content-Type": "text/plain"}))
+	assert.Equal(t, http.StatusUnsupportedMediaType, serveRequest("PATCH", "/tasks/1", body, nil))
+	assert.Equal(t, http.StatusCreated, serveRequest("POST", "/tasks", body, json))
+	assert.Equal(t, http.StatusCreated, serveRequest("POST", "/tasks", body, map[string]string{"Content-Type": "application/json; charset=utf-8"}))
+
+	evil := map[string]string{"Origin": "https://example.com", "Content-Type": "application/json"}
+	assert.Equal(t, http.StatusForbidden, serveRequest("DELETE", "/tasks/1", "", evil))
+	assert.Equal(t, http.StatusForbidden, serveRequest("POST", "/tasks/1/done", "", evil))
+	assert.Equal(t, http.StatusOK, serveRequest("GET", "/tasks", "", evil))
+
+	local := map[string]string{"Origin": "http://127.0.0.1:7777"}
+	assert.Equal(t, http.StatusOK, serveRequest("POST", "/tasks/1/done", "", local))
+}
+
+func TestServeRejectsForeignHosts(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Buy milk")
+
+	json := map[string]string{"Content-Type": "application/json"}
+
+	// A page using DNS rebinding sends its own domain as the host and origin
+	evil := map[string]string{"Origin": "http://attacker.com:7777", "Content-Type": "application/json"}
+	assert.Equal(t, http.StatusForbidden, serveHost(defaultAddr, "attacker.com:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusForbidden, serveHost(defaultAddr, "attacker.com:7777", "PATCH", "/tasks/1", `{"title": "Pwned"}`, evil))
+	assert.Equal(t, http.StatusForbidden, serveHost("0.0.0.0:7777", "attacker.com:7777", "GET", "/tasks", "", nil))
+
+	assert.Equal(t, http.StatusOK, serveHost(defaultAddr, "localhost:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusOK, serveHost(defaultAddr, "[::1]:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusOK, serveHost("tasks.lan:7777", "tasks.lan:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusOK, serveHost("0.0.0.0:7777", "192.168.1.10:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusForbidden, serveHost(defaultAddr, "192.168.1.10:7777", "GET", "/tasks", "", nil))
+	assert.Equal(t, http.StatusOK, serveHost(defaultAddr, "127.0.0.1:7777", "PATCH", "/tasks/1", `{"title": "Buy oat milk"}`, json))
+}
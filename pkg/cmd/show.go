@@ -1,11 +1,136 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
 )
 
+func formatTime(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Format(time.DateTime), utils.ShortDuration(t))
+}
+
+// Formats an optional date, returning an empty string if it isn't set.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Local().Format(time.DateOnly)
+}
+
+// Returns the number of checked items in the checklist of a task, or an empty
+// string if the task doesn't have a checklist.
+func checklistProgress(task storage.Task) string {
+	if len(task.Checklist) == 0 {
+		return ""
+	}
+
+	return task.ChecklistProgress().String()
+}
+
+// Returns the name/value rows describing a task. Optional fields are only
+// included when they are set.
+func taskDetails(task storage.Task) []printer.Row {
+	rows := []printer.Row{
+		{Cells: []string{"ID", strconv.Itoa(task.ShortId)}},
+		{Cells: []string{"UUID", task.Id}},
+		{Cells: []string{"Title", task.Title}},
+		{Cells: []string{"Status", string(task.Status)}},
+		{Cells: []string{"Priority", task.Priority}},
+		{Cells: []string{"Tags", strings.Join(task.Tags, " ")}},
+	}
+
+	if task.Project != "" {
+		rows = append(rows, printer.Row{Cells: []string{"Project", task.Project}})
+	}
+
+	if task.Due != nil {
+		rows = append(rows, printer.Row{Cells: []string{"Due", formatDate(task.Due)}})
+	}
+
+	if task.Scheduled != nil {
+		rows = append(rows, printer.Row{Cells: []string{"Scheduled", formatDate(task.Scheduled)}})
+	}
+
+	if task.Wait != nil {
+		rows = append(rows, printer.Row{Cells: []string{"Wait", formatDate(task.Wait)}})
+	}
+
+	for i, item := range task.Checklist {
+		name := ""
+		if i == 0 {
+			name = "Checklist"
+		}
+
+		box := "[ ]"
+		if item.Done {
+			box = "[x]"
+		}
+
+		rows = append(rows, printer.Row{Cells: []string{name, fmt.Sprintf("%s %d. %s", box, i+1, item.Text)}})
+	}
+
+	keys := make([]string, 0, len(task.Attributes))
+	for key := range task.Attributes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		rows = append(rows, printer.Row{Cells: []string{key, task.Attributes[key]}})
+	}
+
+	rows = append(rows,
+		printer.Row{Cells: []string{"Created", formatTime(task.CreatedAt)}},
+		printer.Row{Cells: []string{"Updated", formatTime(task.UpdatedAt)}},
+	)
+
+	if task.CompletedAt != nil {
+		rows = append(rows, printer.Row{Cells: []string{"Completed", formatTime(*task.CompletedAt)}})
+	}
+
+	return rows
+}
+
 func Show(ctx arg_parser.ParseContext) {
-	fmt.Println("not implemented")
+	requireFilters(ctx, "show")
+
+	format := getFormat(ctx)
+	filters := buildFilters(ctx)
+	tasks, err := storage.GetTasks(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if format.IsData() {
+		printer.JSON(format, tasks)
+		return
+	}
+
+	if len(tasks) == 0 {
+		printer.Error(errors.New("No tasks match filters"))
+	}
+
+	for i, task := range tasks {
+		if i > 0 && format == printer.FormatTable {
+			fmt.Println()
+		}
+
+		table := printer.Table{
+			Columns:  []string{"Name", "Value"},
+			Rows:     append(taskDetails(task), subtaskDetails(task)...),
+			Overflow: getOverflow(ctx),
+		}
+
+		table.PrintAs(format)
+	}
 }
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+const (
+	defaultStatsWeeks = 4
+	topTagsCount      = 10
+)
+
+type weekStats struct {
+	// The first day (Monday) of the week
+	Week      string `json:"week"`
+	Added     int    `json:"added"`
+	Completed int    `json:"completed"`
+}
+
+type tagStats struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type priorityStats struct {
+	Priority string `json:"priority"`
+	Count    int    `json:"count"`
+}
+
+type stats struct {
+	Total   int `json:"total"`
+	Pending int `json:"pending"`
+	Active  int `json:"active"`
+	Done    int `json:"done"`
+	// The oldest task that has not been started or completed
+	OldestPending *storage.Task `json:"oldest_pending"`
+	// The average age of pending tasks in seconds
+	AveragePendingAge int64 `json:"average_pending_age"`
+	// The tasks added and completed in each of the last weeks, oldest first
+	Weeks []weekStats `json:"weeks"`
+	// The most used tags of open tasks, most used first
+	Tags []tagStats `json:"tags"`
+	// The number of open tasks with each priority, highest priority first
+	Priorities []priorityStats `json:"priorities"`
+}
+
+// Returns the start of the week (Monday) containing the time.
+func startOfWeek(t time.Time) time.Time {
+	t = t.Local()
+	offset := (int(t.Weekday()) + 6) % 7
+
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// Returns the time a task was completed. Tasks completed before the completion
+// time was recorded use the time they were last updated.
+func completionTime(task storage.Task) time.Time {
+	if task.CompletedAt != nil {
+		return *task.CompletedAt
+	}
+
+	return task.UpdatedAt
+}
+
+// Sorts counts by the highest count first, breaking ties by name.
+func sortCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// Sorts the priorities from highest to lowest. Tasks without a priority or with
+// a priority that is no longer configured are sorted last by count.
+func sortPriorities(counts map[string]int) []string {
+	keys := sortCounts(counts)
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := storage.PriorityRank(keys[i]), storage.PriorityRank(keys[j])
+		if a < 0 || b < 0 {
+			return a >= 0 && b < 0
+		}
+
+		return a < b
+	})
+
+	return keys
+}
+
+func computeStats(tasks []storage.Task, now time.Time, weeks int) stats {
+	result := stats{
+		Total:      len(tasks),
+		Weeks:      make([]weekStats, weeks),
+		Tags:       []tagStats{},
+		Priorities: []priorityStats{},
+	}
+
+	// Index of each week by its start date
+	first := startOfWeek(now).AddDate(0, 0, -7*(weeks-1))
+	weekIndex := func(t time.Time) int {
+		start := startOfWeek(t)
+		if start.Before(first) {
+			return -1
+		}
+
+		// Days are rounded since they may not be 24 hours long with DST
+		days := int(math.Round(start.Sub(first).Hours() / 24))
+		if i := days / 7; i < weeks {
+			return i
+		}
+
+		return -1
+	}
+
+	for i := range result.Weeks {
+		result.Weeks[i].Week = first.AddDate(0, 0, 7*i).Format(time.DateOnly)
+	}
+
+	tags := make(map[string]int)
+	priorities := make(map[string]int)
+	var totalAge time.Duration
+
+	for i, task := range tasks {
+		if w := weekIndex(task.CreatedAt); w != -1 {
+			result.Weeks[w].Added++
+		}
+
+		switch task.Status {
+		case storage.TaskStatusDone:
+			result.Done++
+
+			if w := weekIndex(completionTime(task)); w != -1 {
+				result.Weeks[w].Completed++
+			}
+
+			continue
+		case storage.TaskStatusActive:
+			result.Active++
+		default:
+			result.Pending++
+			totalAge += now.Sub(task.CreatedAt)
+
+			if result.OldestPending == nil || task.CreatedAt.Before(result.OldestPending.CreatedAt) {
+				result.OldestPending = &tasks[i]
+			}
+		}
+
+		for _, tag := range task.Tags {
+			tags[tag]++
+		}
+
+		priorities[task.Priority]++
+	}
+
+	if result.Pending > 0 {
+		result.AveragePendingAge = int64((totalAge / time.Duration(result.Pending)).Seconds())
+	}
+
+	for i, tag := range sortCounts(tags) {
+		if i == topTagsCount {
+			break
+		}
+
+		result.Tags = append(result.Tags, tagStats{Tag: tag, Count: tags[tag]})
+	}
+
+	for _, priority := range sortPriorities(priorities) {
+		result.Priorities = append(result.Priorities, priorityStats{Priority: priority, Count: priorities[priority]})
+	}
+
+	return result
+}
+
+func printStatsTable(format printer.Format, overflow printer.Overflow, columns []string, rows []printer.Row) {
+	if format == printer.FormatTable {
+		fmt.Println()
+	}
+
+	table := printer.Table{Columns: columns, Rows: rows, Overflow: overflow}
+	table.PrintAs(format)
+}
+
+// Prints statistics about the tasks matching the filters, including completed
+// tasks.
+func Stats(ctx arg_parser.ParseContext) {
+	weeks := defaultStatsWeeks
+	if value, ok := getFlag(ctx, "weeks"); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			printer.Error(fmt.Errorf("Invalid number of weeks \"%s\"", value))
+		}
+
+		weeks = n
+	}
+
+	tasks := reportTasks(ctx)
+	now := time.Now()
+	result := computeStats(tasks, now, weeks)
+	format := getFormat(ctx)
+
+	if format.IsData() {
+		printer.JSONValue(format, result)
+		return
+	}
+
+	overflow := getOverflow(ctx)
+	summary := []printer.Row{
+		{Cells: []string{"Total", strconv.Itoa(result.Total)}},
+		{Cells: []string{"Pending", strconv.Itoa(result.Pending)}},
+		{Cells: []string{"Active", strconv.Itoa(result.Active)}},
+		{Cells: []string{"Done", strconv.Itoa(result.Done)}},
+	}
+
+	if task := result.OldestPending; task != nil {
+		summary = append(summary,
+			printer.Row{Cells: []string{
+				"Oldest pending",
+				fmt.Sprintf("%d %s (%s)", task.ShortId, task.Title, utils.ShortDuration(task.CreatedAt)),
+			}},
+			printer.Row{Cells: []string{
+				"Average pending age",
+				utils.ShortDuration(now.Add(-time.Duration(result.AveragePendingAge) * time.Second)),
+			}},
+		)
+	}
+
+	table := printer.Table{Columns: []string{"Name", "Value"}, Rows: summary, Overflow: overflow}
+	table.PrintAs(format)
+
+	var weekRows []printer.Row
+	for _, week := range result.Weeks {
+		weekRows = append(weekRows, printer.Row{Cells: []string{
+			week.Week,
+			strconv.Itoa(week.Added),
+			strconv.Itoa(week.Completed),
+		}})
+	}
+
+	printStatsTable(format, overflow, []string{"Week", "Added", "Completed"}, weekRows)
+
+	if len(result.Tags) > 0 {
+		var tagRows []printer.Row
+		for _, tag := range result.Tags {
+			tagRows = append(tagRows, printer.Row{Cells: []string{tag.Tag, strconv.Itoa(tag.Count)}})
+		}
+
+		printStatsTable(format, overflow, []string{"Tag", "Tasks"}, tagRows)
+	}
+
+	if len(result.Priorities) > 0 {
+		var priorityRows []printer.Row
+		for _, priority := range result.Priorities {
+			name := priority.Priority
+			if name == "" {
+				name = "None"
+			}
+
+			priorityRows = append(priorityRows, printer.Row{Cells: []string{name, strconv.Itoa(priority.Count)}})
+		}
+
+		printStatsTable(format, overflow, []string{"Priority", "Tasks"}, priorityRows)
+	}
+}
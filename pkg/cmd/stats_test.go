@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func localTime(month time.Month, day int, hour int, min int) time.Time {
+	return time.Date(2026, month, day, hour, min, 0, 0, time.Local)
+}
+
+func TestComputeStats(t *testing.T) {
+	// A Wednesday, so the last two weeks start on October 12 and 19
+	now := localTime(10, 21, 12, 0)
+	completed := localTime(10, 19, 9, 0)
+
+	tests := []struct {
+		name     string
+		tasks    []storage.Task
+		expected stats
+	}{
+		{
+			name:  "empty",
+			tasks: []storage.Task{},
+			expected: stats{
+				Weeks: []weekStats{
+					{Week: "2026-10-12"},
+					{Week: "2026-10-19"},
+				},
+				Tags:       []tagStats{},
+				Priorities: []priorityStats{},
+			},
+		},
+		{
+			name: "week boundaries",
+			tasks: []storage.Task{
+				{Status: storage.TaskStatusDone, CreatedAt: localTime(10, 11, 23, 59), UpdatedAt: localTime(10, 18, 23, 59)},
+				{Status: storage.TaskStatusDone, CreatedAt: localTime(10, 12, 0, 0), CompletedAt: &completed, UpdatedAt: now},
+				{Status: storage.TaskStatusDone, CreatedAt: localTime(10, 19, 0, 0), UpdatedAt: localTime(10, 19, 0, 0)},
+			},
+			expected: stats{
+				Total: 3,
+				Done:  3,
+				Weeks: []weekStats{
+					{Week: "2026-10-12", Added: 1, Completed: 1},
+					{Week: "2026-10-19", Added: 1, Completed: 2},
+				},
+				Tags:       []tagStats{},
+				Priorities: []priorityStats{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, computeStats(test.tasks, now, 2))
+		})
+	}
+}
+
+func TestComputeStatsOpenTasks(t *testing.T) {
+	now := localTime(10, 21, 12, 0)
+	tasks := []storage.Task{
+		{Status: storage.TaskStatusPending, Priority: "L", Tags: []string{"home"}, CreatedAt: now.AddDate(0, 0, -1)},
+		{Status: storage.TaskStatusPending, Priority: "H", Tags: []string{"work", "home"}, CreatedAt: now.AddDate(0, 0, -3)},
+		{Status: storage.TaskStatusActive, Priority: "", Tags: []string{"work"}, CreatedAt: now.AddDate(0, 0, -30)},
+		{Status: storage.TaskStatusPending, Priority: "H", Tags: []string{}, CreatedAt: now.AddDate(0, 0, -2)},
+		{Status: storage.TaskStatusDone, Priority: "M", Tags: []string{"done"}, CreatedAt: now, UpdatedAt: now},
+	}
+
+	result := computeStats(tasks, now, 1)
+
+	assert.Equal(t, 5, result.Total)
+	assert.Equal(t, 3, result.Pending)
+	assert.Equal(t, 1, result.Active)
+	assert.Equal(t, 1, result.Done)
+	assert.Equal(t, &tasks[1], result.OldestPending)
+	assert.Equal(t, int64(2*24*60*60), result.AveragePendingAge)
+	assert.Equal(t, []weekStats{{Week: "2026-10-19", Added: 3, Completed: 1}}, result.Weeks)
+	assert.Equal(t, []tagStats{{Tag: "home", Count: 2}, {Tag: "work", Count: 2}}, result.Tags)
+	assert.Equal(t, []priorityStats{
+		{Priority: "H", Count: 2},
+		{Priority: "L", Count: 1},
+		{Priority: "", Count: 1},
+	}, result.Priorities)
+}
+
+func TestSortPriorities(t *testing.T) {
+	defer storage.SetPriorities([]string{"H", "M", "L"})
+	assert.NoError(t, storage.SetPriorities([]string{"P0", "P1", "P2"}))
+
+	counts := map[string]int{"P2": 5, "P0": 1, "": 2, "H": 3, "P1": 1}
+	assert.Equal(t, []string{"P0", "P1", "P2", "H", ""}, sortPriorities(counts))
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Adds a subtask to the task matching the filters.
+func Subtask(ctx arg_parser.ParseContext) {
+	parent := singleTask(ctx, "subtask")
+
+	task := storage.NewTask()
+	applyArgs(&task, ctx)
+	task.Parent = parent.Id
+
+	if task.Title == "" {
+		printer.Error(errors.New("Missing title"))
+	}
+
+	id, err := storage.Add(task)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	fmt.Println("Created task", id)
+}
+
+// A task in the task tree along with its depth below the top-level tasks
+type treeTask struct {
+	storage.Task
+	Depth int
+}
+
+// Orders the tasks so each subtask follows its parent, keeping the order of
+// tasks with the same parent. Subtasks whose parent isn't in the list are
+// shown as top-level tasks.
+func taskTree(tasks []storage.Task) []treeTask {
+	ids := make(map[string]bool)
+	for _, task := range tasks {
+		ids[task.Id] = true
+	}
+
+	children := make(map[string][]storage.Task)
+	for _, task := range tasks {
+		if task.Parent != "" && ids[task.Parent] {
+			children[task.Parent] = append(children[task.Parent], task)
+		}
+	}
+
+	tree := []treeTask{}
+	added := make(map[string]bool)
+
+	var add func(task storage.Task, depth int)
+	add = func(task storage.Task, depth int) {
+		if added[task.Id] {
+			return
+		}
+
+		added[task.Id] = true
+		tree = append(tree, treeTask{Task: task, Depth: depth})
+
+		for _, child := range children[task.Id] {
+			add(child, depth+1)
+		}
+	}
+
+	for _, task := range tasks {
+		if task.Parent == "" || !ids[task.Parent] {
+			add(task, 0)
+		}
+	}
+
+	// Tasks that are their own ancestors are never reached from a top-level
+	// task, so they are added at the top level.
+	for _, task := range tasks {
+		add(task, 0)
+	}
+
+	return tree
+}
+
+// Formats the title of a task in the task tree. Subtasks are indented below
+// their parent when showing a table, and parents include the number of
+// completed subtasks.
+func treeTitle(task treeTask, progress map[string]storage.Progress, format printer.Format) string {
+	title := task.Title
+
+	if p, ok := progress[task.Id]; ok {
+		title += fmt.Sprintf(" (%s)", p)
+	}
+
+	if task.Depth > 0 && format == printer.FormatTable {
+		title = strings.Repeat("  ", task.Depth-1) + "└ " + title
+	}
+
+	return title
+}
+
+// Returns the rows describing the parent and subtasks of a task, if any.
+func subtaskDetails(task storage.Task) []printer.Row {
+	rows := []printer.Row{}
+
+	if task.Parent != "" {
+		parents, err := storage.GetTasks([]sql_builder.Filter{taskFilter(task.Parent)})
+		if err != nil {
+			printer.Error(err)
+		}
+
+		if len(parents) > 0 {
+			parent := fmt.Sprintf("%d %s", parents[0].ShortId, parents[0].Title)
+			rows = append(rows, printer.Row{Cells: []string{"Parent", parent}})
+		}
+	}
+
+	progress, err := storage.SubtaskProgress()
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if p, ok := progress[task.Id]; ok {
+		rows = append(rows, printer.Row{Cells: []string{"Subtasks", p.String()}})
+	}
+
+	return rows
+}
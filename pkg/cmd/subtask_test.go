@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the ids and depths of the task tree as `id:depth` pairs.
+func treeOrder(tree []treeTask) []string {
+	order := []string{}
+	for _, task := range tree {
+		order = append(order, fmt.Sprintf("%s:%d", task.Id, task.Depth))
+	}
+
+	return order
+}
+
+func TestTaskTree(t *testing.T) {
+	tests := []struct {
+		name     string
+		tasks    []storage.Task
+		expected []string
+	}{
+		{
+			name: "nested",
+			tasks: []storage.Task{
+				{Id: "c", Parent: "b"},
+				{Id: "a"},
+				{Id: "b", Parent: "a"},
+				{Id: "d", Parent: "a"},
+				{Id: "e"},
+			},
+			expected: []string{"a:0", "b:1", "c:2", "d:1", "e:0"},
+		},
+		{
+			name: "orphans",
+			tasks: []storage.Task{
+				{Id: "b", Parent: "filtered"},
+				{Id: "a"},
+				{Id: "c", Parent: "b"},
+			},
+			expected: []string{"b:0", "c:1", "a:0"},
+		},
+		{
+			name: "cycle",
+			tasks: []storage.Task{
+				{Id: "a"},
+				{Id: "b", Parent: "c"},
+				{Id: "c", Parent: "b"},
+				{Id: "d", Parent: "d"},
+			},
+			expected: []string{"a:0", "b:0", "c:1", "d:0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, treeOrder(taskTree(test.tasks)))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Returns the sync directory from the command args, falling back to the
+// `sync=` config override. A leading `~` is expanded to the home directory
+// since the config file is not expanded by the shell.
+func getSyncDir(ctx arg_parser.ParseContext) string {
+	dir := firstTextArg(ctx)
+	if dir == "" {
+		for _, config := range ctx.Config {
+			if config, ok := config.(arg_parser.SyncConfig); ok {
+				dir = config.Dir
+			}
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+
+	return dir
+}
+
+func Sync(ctx arg_parser.ParseContext) {
+	dir := getSyncDir(ctx)
+	if dir == "" {
+		printer.Error(errors.New("Missing sync directory"))
+		return
+	}
+
+	result, err := storage.Sync(dir)
+	if err != nil {
+		printer.Error(err)
+		return
+	}
+
+	fmt.Printf(
+		"Imported %d changes from %d replicas, exported %d changes\n",
+		result.Imported,
+		result.Replicas,
+		result.Exported,
+	)
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Lists every tag with the number of open and completed tasks using it.
+func Tags(ctx arg_parser.ParseContext) {
+	format := getFormat(ctx)
+	counts, err := storage.CountTags()
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if format.IsData() {
+		printer.JSON(format, counts)
+		return
+	}
+
+	if len(counts) == 0 && format == printer.FormatTable {
+		printer.Message("No tags found")
+		return
+	}
+
+	table := printer.Table{
+		Columns:  []string{"Tag", "Pending", "Done"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, count := range counts {
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{count.Tag, strconv.Itoa(count.Pending), strconv.Itoa(count.Done)},
+			Style: printer.RuleStyle("", []string{count.Tag}),
+		})
+	}
+
+	table.PrintAs(format)
+}
+
+// Returns the action and tag names from the command args. Tag names can be
+// written with or without a leading `+`.
+func tagCommandArgs(ctx arg_parser.ParseContext) (string, []string) {
+	var action string
+	var names []string
+
+	for _, arg := range ctx.Args {
+		switch arg := arg.(type) {
+		case arg_parser.TextArg:
+			words := strings.Fields(arg.Text)
+			action, names = words[0], append(words[1:], names...)
+		case arg_parser.TagArg:
+			names = append(names, arg.Tag)
+		}
+	}
+
+	return action, names
+}
+
+// Renames, merges, or deletes a tag across all tasks matching the filters.
+func Tag(ctx arg_parser.ParseContext) {
+	action, names := tagCommandArgs(ctx)
+
+	var tag, replacement, message, verb string
+
+	switch action {
+	case "rename":
+		if len(names) != 2 {
+			printer.Error(errors.New("Usage: tsk tag rename <old> <new>"))
+		}
+
+		tag, replacement = names[0], names[1]
+		message = fmt.Sprintf("rename the tag \"%s\" to \"%s\" in", tag, replacement)
+		verb = "Renamed tag in"
+
+		// Renaming to an existing tag would silently merge the tags
+		if count, err := storage.Count(append(buildFilters(ctx), tagFilter(replacement, arg_parser.Include, false))); err != nil {
+			printer.Error(err)
+		} else if count > 0 {
+			printer.Error(fmt.Errorf("The tag \"%s\" already exists, use `tsk tag merge` to merge tags", replacement))
+		}
+
+	case "merge":
+		if len(names) != 2 {
+			printer.Error(errors.New("Usage: tsk tag merge <from> <into>"))
+		}
+
+		tag, replacement = names[0], names[1]
+		message = fmt.Sprintf("merge the tag \"%s\" into \"%s\" in", tag, replacement)
+		verb = "Merged tag in"
+
+	case "delete":
+		if len(names) != 1 {
+			printer.Error(errors.New("Usage: tsk tag delete <tag>"))
+		}
+
+		tag = names[0]
+		message = fmt.Sprintf("remove the tag \"%s\" from", tag)
+		verb = "Removed tag from"
+
+	case "":
+		printer.Error(errors.New("Missing action, expected one of rename, merge, or delete"))
+
+	default:
+		printer.Error(fmt.Errorf("Invalid action \"%s\", expected one of rename, merge, or delete", action))
+	}
+
+	if tag == replacement {
+		printer.Error(errors.New("The tags must be different"))
+	}
+
+	// Tags are changed one at a time, so patterns are not supported
+	if isTagPattern(tag) || isTagPattern(replacement) {
+		printer.Error(errors.New("Tag names can't contain wildcards"))
+	}
+
+	filters := append(buildFilters(ctx), tagFilter(tag, arg_parser.Include, false))
+	tasks, err := storage.GetTasks(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if len(tasks) == 0 {
+		printer.Error(fmt.Errorf("No tasks have the tag \"%s\"", tag))
+	}
+
+	previewTags(ctx, tasks, tag, replacement)
+
+	count := len(tasks)
+	fmt.Printf("\nThis command will %s %d %s\n", message, count, utils.Pluralize(count, "task", "tasks"))
+
+	if utils.IsBulk(ctx, count) && !confirm(ctx) {
+		return
+	}
+
+	ids, err := storage.ReplaceTag(filters, tag, replacement)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	for _, id := range ids {
+		fmt.Printf("%s task %d\n", verb, id)
+	}
+}
+
+// Prints the tags of each task before and after the change.
+func previewTags(ctx arg_parser.ParseContext, tasks []storage.Task, tag string, replacement string) {
+	table := printer.Table{
+		Columns:  []string{"ID", "Tags", "New Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, task := range tasks {
+		tags := storage.ReplaceTagIn(task.Tags, tag, replacement)
+
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				strings.Join(task.Tags, " "),
+				strings.Join(tags, " "),
+				task.Title,
+			},
+		})
+	}
+
+	table.Print()
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+// Lists the deleted tasks matching the filters, most recently deleted first.
+func Trash(ctx arg_parser.ParseContext) {
+	format := getFormat(ctx)
+	tasks, err := storage.ListTrash(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+	}
+
+	if format.IsData() {
+		printer.JSON(format, tasks)
+		return
+	}
+
+	if len(tasks) == 0 && format == printer.FormatTable {
+		printer.Message("The trash is empty")
+		return
+	}
+
+	printTrash(ctx, tasks, format)
+}
+
+func printTrash(ctx arg_parser.ParseContext, tasks []storage.Task, format printer.Format) {
+	table := printer.Table{
+		Columns:  []string{"ID", "Deleted", "P", "Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, task := range tasks {
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				utils.ShortDuration(*task.DeletedAt),
+				task.Priority,
+				strings.Join(task.Tags, " "),
+				task.Title,
+			},
+		})
+	}
+
+	table.PrintAs(format)
+}
+
+// Moves the deleted tasks matching the filters back to the task list.
+func Restore(ctx arg_parser.ParseContext) {
+	requireFilters(ctx, "restore")
+
+	filters := buildFilters(ctx)
+	tasks, err := storage.ListTrash(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	count := len(tasks)
+	if count == 0 {
+		printer.Error(errors.New("No deleted tasks match filters"))
+	}
+
+	if utils.IsBulk(ctx, count) {
+		fmt.Printf("This command will restore %d tasks\n", count)
+
+		if !confirm(ctx) {
+			return
+		}
+	}
+
+	ids, err := storage.Restore(filters)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Restored task %d\n", id)
+	}
+}
+
+// Permanently removes the deleted tasks matching the filters. The
+// `older-than:` arg limits the tasks to those deleted before the given age.
+func Purge(ctx arg_parser.ParseContext) {
+	cutoff, err := getOlderThan(ctx)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	filters := buildFilters(ctx)
+	tasks, err := storage.ListPurgeable(filters, cutoff)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	count := len(tasks)
+	if count == 0 {
+		printer.Message("No deleted tasks to purge")
+		return
+	}
+
+	printTrash(ctx, tasks, printer.FormatTable)
+	fmt.Printf("\nThis command will permanently delete %d %s\n", count, utils.Pluralize(count, "task", "tasks"))
+
+	if !confirm(ctx) {
+		return
+	}
+
+	ids, err := storage.Purge(filters, cutoff)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	for _, id := range ids {
+		fmt.Printf("Purged task %d\n", id)
+	}
+}
@@ -0,0 +1,547 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/terminal"
+	"github.com/mskelton/tsk/internal/utils"
+)
+
+type uiMode int
+
+const (
+	uiNormal uiMode = iota
+	uiFilter
+	uiEdit
+	uiConfirmDelete
+)
+
+const uiHelp = "j/k move  / filter  enter details  s start  S stop  d done  x delete  e edit  q quit"
+
+// A single line text input used by the filter prompt and the edit form.
+type lineInput struct {
+	label string
+	text  []rune
+	pos   int
+}
+
+func newLineInput(label string, text string) lineInput {
+	runes := []rune(text)
+	return lineInput{label: label, text: runes, pos: len(runes)}
+}
+
+func (input *lineInput) String() string {
+	return string(input.text)
+}
+
+// Handles a key press, returning false if the key was not handled.
+func (input *lineInput) handle(key terminal.Key) bool {
+	switch key.Code {
+	case terminal.KeyRune:
+		input.text = append(input.text[:input.pos], append([]rune{key.Rune}, input.text[input.pos:]...)...)
+		input.pos++
+	case terminal.KeyBackspace:
+		if input.pos > 0 {
+			input.text = append(input.text[:input.pos-1], input.text[input.pos:]...)
+			input.pos--
+		}
+	case terminal.KeyDelete:
+		if input.pos < len(input.text) {
+			input.text = append(input.text[:input.pos], input.text[input.pos+1:]...)
+		}
+	case terminal.KeyLeft:
+		input.pos = max(0, input.pos-1)
+	case terminal.KeyRight:
+		input.pos = min(len(input.text), input.pos+1)
+	case terminal.KeyHome:
+		input.pos = 0
+	case terminal.KeyEnd:
+		input.pos = len(input.text)
+	case terminal.KeyCtrlU:
+		input.text = input.text[input.pos:]
+		input.pos = 0
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Renders the input, returning the line and the column of the cursor.
+func (input *lineInput) render() (string, int) {
+	label := input.label + " "
+	col := runewidth.StringWidth(label) + runewidth.StringWidth(string(input.text[:input.pos]))
+	return label + string(input.text), col
+}
+
+type ui struct {
+	// Filters from the command line that are always applied
+	filters []sql_builder.Filter
+	tasks   []storage.Task
+	cursor  int
+	offset  int
+	query   string
+	mode    uiMode
+	details bool
+	message string
+	width   int
+	height  int
+
+	// The filter prompt
+	filter lineInput
+	// The inline edit form for the title, tags, and priority
+	fields []lineInput
+	field  int
+}
+
+// Loads the tasks matching the current filter query.
+func (u *ui) load() {
+	// Prefixing the query with the list command ensures the parser treats
+	// every word of the query as a filter, even if it matches a command name.
+	parser := arg_parser.New()
+	ctx := parser.Parse(append([]string{"list"}, strings.Fields(u.query)...))
+
+	query, err := parseFilters(ctx)
+	if err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	filters := append(append([]sql_builder.Filter{}, u.filters...), query...)
+	tasks, err := storage.ListTasks(filters)
+	if err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	u.tasks = tasks
+	u.cursor = max(0, min(u.cursor, len(u.tasks)-1))
+}
+
+func (u *ui) selected() (storage.Task, bool) {
+	if len(u.tasks) == 0 {
+		return storage.Task{}, false
+	}
+
+	return u.tasks[u.cursor], true
+}
+
+// Applies edits to the selected task and reloads the task list.
+func (u *ui) edit(verb string, edits []storage.QueryEdit) {
+	task, ok := u.selected()
+	if !ok {
+		return
+	}
+
+	if _, err := storage.Edit([]sql_builder.Filter{taskFilter(task.Id)}, edits); err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	u.message = fmt.Sprintf("%s task %d", verb, task.ShortId)
+	u.load()
+}
+
+func (u *ui) setStatus(verb string, status storage.TaskStatus) {
+	u.edit(verb, []storage.QueryEdit{{Path: "status", Value: string(status)}})
+}
+
+func (u *ui) delete() {
+	task, ok := u.selected()
+	if !ok {
+		return
+	}
+
+	if _, err := storage.Delete([]sql_builder.Filter{taskFilter(task.Id)}); err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	u.message = fmt.Sprintf("Deleted task %d", task.ShortId)
+	u.load()
+}
+
+func (u *ui) startEdit() {
+	task, ok := u.selected()
+	if !ok {
+		return
+	}
+
+	u.mode = uiEdit
+	u.field = 0
+	u.fields = []lineInput{
+		newLineInput("Title:   ", task.Title),
+		newLineInput("Tags:    ", strings.Join(task.Tags, " ")),
+		newLineInput("Priority:", task.Priority),
+	}
+}
+
+func (u *ui) saveEdit() {
+	task, ok := u.selected()
+	if !ok {
+		return
+	}
+
+	title := strings.TrimSpace(u.fields[0].String())
+	if title == "" {
+		u.message = "Missing title"
+		return
+	}
+
+	priority, err := storage.ParsePriority(strings.TrimSpace(u.fields[2].String()))
+	if err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	task.Title = title
+	task.Priority = priority
+	task.Tags = []string{}
+
+	for _, tag := range strings.Fields(u.fields[1].String()) {
+		if tag = strings.TrimPrefix(tag, "+"); tag != "" {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	if err := storage.Update(task); err != nil {
+		u.message = err.Error()
+		return
+	}
+
+	u.mode = uiNormal
+	u.message = fmt.Sprintf("Edited task %d", task.ShortId)
+	u.load()
+}
+
+func (u *ui) move(delta int) {
+	u.cursor = max(0, min(len(u.tasks)-1, u.cursor+delta))
+}
+
+// Handles a key press, returning false when the UI should exit.
+func (u *ui) handle(key terminal.Key) bool {
+	if key.Code == terminal.KeyCtrlC {
+		return false
+	}
+
+	if key.Code == terminal.KeyResize {
+		return true
+	}
+
+	switch u.mode {
+	case uiFilter:
+		switch key.Code {
+		case terminal.KeyEnter:
+			u.query = strings.TrimSpace(u.filter.String())
+			u.mode = uiNormal
+			u.cursor = 0
+			u.load()
+		case terminal.KeyEscape:
+			u.mode = uiNormal
+		default:
+			u.filter.handle(key)
+		}
+
+	case uiEdit:
+		switch key.Code {
+		case terminal.KeyEnter:
+			u.saveEdit()
+		case terminal.KeyEscape:
+			u.mode = uiNormal
+		case terminal.KeyTab, terminal.KeyDown:
+			u.field = (u.field + 1) % len(u.fields)
+		case terminal.KeyUp:
+			u.field = (u.field + len(u.fields) - 1) % len(u.fields)
+		default:
+			u.fields[u.field].handle(key)
+		}
+
+	case uiConfirmDelete:
+		u.mode = uiNormal
+
+		if key.Code == terminal.KeyRune && key.Rune == 'y' {
+			u.delete()
+		} else {
+			u.message = ""
+		}
+
+	default:
+		u.message = ""
+
+		switch key.Code {
+		case terminal.KeyDown:
+			u.move(1)
+		case terminal.KeyUp:
+			u.move(-1)
+		case terminal.KeyHome:
+			u.move(-len(u.tasks))
+		case terminal.KeyEnd:
+			u.move(len(u.tasks))
+		case terminal.KeyEnter, terminal.KeyTab:
+			u.details = !u.details
+		case terminal.KeyEscape:
+			if u.query != "" {
+				u.query = ""
+				u.load()
+			}
+		case terminal.KeyRune:
+			switch key.Rune {
+			case 'q':
+				return false
+			case 'j':
+				u.move(1)
+			case 'k':
+				u.move(-1)
+			case 'g':
+				u.move(-len(u.tasks))
+			case 'G':
+				u.move(len(u.tasks))
+			case '/':
+				u.mode = uiFilter
+				u.filter = newLineInput("/", u.query)
+			case 's':
+				u.setStatus("Started", storage.TaskStatusActive)
+			case 'S':
+				u.setStatus("Stopped", storage.TaskStatusPending)
+			case 'd':
+				u.setStatus("Completed", storage.TaskStatusDone)
+			case 'x':
+				if _, ok := u.selected(); ok {
+					u.mode = uiConfirmDelete
+				}
+			case 'e':
+				u.startEdit()
+			case 'r':
+				u.load()
+			}
+		}
+	}
+
+	return true
+}
+
+// Fits text to exactly the given width, truncating or padding as needed.
+func fit(text string, width int) string {
+	text = runewidth.Truncate(text, width, "…")
+	return text + strings.Repeat(" ", max(0, width-runewidth.StringWidth(text)))
+}
+
+func (u *ui) listLines(height int) []string {
+	theme := printer.CurrentTheme()
+
+	if len(u.tasks) == 0 {
+		return []string{"No tasks match filters"}
+	}
+
+	columns := []string{"ID", "Age", "P", "Tags", "Title"}
+	rows := make([][]string, len(u.tasks))
+	widths := make([]int, len(columns))
+
+	for i, col := range columns {
+		widths[i] = len(col)
+	}
+
+	for i, task := range u.tasks {
+		rows[i] = []string{
+			strconv.Itoa(task.ShortId),
+			utils.ShortDuration(task.CreatedAt),
+			task.Priority,
+			strings.Join(task.Tags, " "),
+			task.Title,
+		}
+
+		for j, cell := range rows[i] {
+			widths[j] = max(widths[j], runewidth.StringWidth(cell))
+		}
+	}
+
+	// The title fills the remaining width, leaving room for the cursor marker
+	used := 2
+	for _, width := range widths[:len(widths)-1] {
+		used += width + 1
+	}
+
+	widths[len(widths)-1] = max(1, u.width-used)
+
+	join := func(cells []string) string {
+		var padded []string
+		for i, cell := range cells {
+			padded = append(padded, fit(cell, widths[i]))
+		}
+
+		return strings.Join(padded, " ")
+	}
+
+	lines := []string{theme.Header.Sprint("  " + join(columns))}
+
+	// Scroll the list so the cursor is always visible
+	visible := max(1, height-1)
+	if u.cursor < u.offset {
+		u.offset = u.cursor
+	} else if u.cursor >= u.offset+visible {
+		u.offset = u.cursor - visible + 1
+	}
+
+	for i := u.offset; i < len(u.tasks) && i < u.offset+visible; i++ {
+		task := u.tasks[i]
+		marker := "  "
+		style := printer.RuleStyle(task.Priority, task.Tags)
+
+		if task.Status == storage.TaskStatusActive {
+			style = append(append(printer.Style{}, theme.Active...), style...)
+		}
+
+		if i == u.cursor {
+			marker = "> "
+			style = append(style, color.ReverseVideo)
+		}
+
+		lines = append(lines, style.Sprint(marker+join(rows[i])))
+	}
+
+	return lines
+}
+
+func (u *ui) detailLines() []string {
+	task, ok := u.selected()
+	if !ok {
+		return nil
+	}
+
+	lines := []string{strings.Repeat("─", u.width)}
+	for _, row := range taskDetails(task) {
+		lines = append(lines, fit(fmt.Sprintf("%-9s %s", row.Cells[0], row.Cells[1]), u.width))
+	}
+
+	return lines
+}
+
+// Renders the full screen. The screen is rendered from the top left corner on
+// every update, clearing the rest of each line to avoid flickering.
+func (u *ui) render() string {
+	if width, height, ok := terminal.Size(); ok {
+		u.width, u.height = width, height
+	}
+
+	var footer []string
+	cursorRow, cursorCol := -1, 0
+
+	switch u.mode {
+	case uiFilter:
+		line, col := u.filter.render()
+		footer = []string{line}
+		cursorRow, cursorCol = 0, col
+
+	case uiEdit:
+		for i := range u.fields {
+			line, col := u.fields[i].render()
+			footer = append(footer, line)
+
+			if i == u.field {
+				cursorRow, cursorCol = i, col
+			}
+		}
+
+		footer = append(footer, "tab next field  enter save  esc cancel")
+
+	case uiConfirmDelete:
+		task, _ := u.selected()
+		footer = []string{fmt.Sprintf("Delete task %d? (y/n)", task.ShortId)}
+
+	default:
+		if u.message != "" {
+			footer = []string{u.message}
+		} else {
+			footer = []string{uiHelp}
+		}
+	}
+
+	title := fmt.Sprintf("tsk  %d %s", len(u.tasks), utils.Pluralize(len(u.tasks), "task", "tasks"))
+	if u.query != "" {
+		title += "  /" + u.query
+	}
+
+	lines := []string{color.New(color.Bold).Sprint(fit(title, u.width))}
+
+	var details []string
+	if u.details {
+		details = u.detailLines()
+	}
+
+	listHeight := u.height - len(lines) - len(footer) - len(details)
+	if u.details && listHeight < 3 {
+		details = nil
+		listHeight = u.height - len(lines) - len(footer)
+	}
+
+	lines = append(lines, u.listLines(listHeight)...)
+	for len(lines) < u.height-len(footer)-len(details) {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, details...)
+	footerRow := len(lines)
+	for _, line := range footer {
+		lines = append(lines, fit(line, u.width))
+	}
+
+	var b strings.Builder
+	b.WriteString(terminal.HideCursor + terminal.MoveTo(0, 0))
+
+	for i, line := range lines {
+		if i >= u.height {
+			break
+		}
+
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+
+		b.WriteString(line + terminal.ClearLine)
+	}
+
+	if cursorRow >= 0 {
+		b.WriteString(terminal.MoveTo(footerRow+cursorRow, cursorCol) + terminal.ShowCursor)
+	}
+
+	return b.String()
+}
+
+func UI(ctx arg_parser.ParseContext) {
+	if !terminal.IsTerminal() {
+		printer.Error(errors.New("The ui command requires a terminal"))
+	}
+
+	u := &ui{filters: buildFilters(ctx), width: 80, height: 24}
+	u.load()
+
+	restore, err := terminal.MakeRaw()
+	if err != nil {
+		printer.Error(fmt.Errorf("Failed to start ui: %w", err))
+	}
+
+	fmt.Print(terminal.EnterAltScreen + terminal.ClearScreen)
+	defer func() {
+		fmt.Print(terminal.ShowCursor + terminal.ExitAltScreen)
+		restore()
+	}()
+
+	fmt.Print(u.render())
+
+	for key := range terminal.ReadKeys() {
+		if !u.handle(key) {
+			return
+		}
+
+		fmt.Print(u.render())
+	}
+}
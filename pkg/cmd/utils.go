@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mskelton/tsk/internal/arg_parser"
 	"github.com/mskelton/tsk/internal/printer"
 	"github.com/mskelton/tsk/internal/sql_builder"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/utils"
 )
 
 func requireFilters(ctx arg_parser.ParseContext, command string) {
@@ -16,53 +21,362 @@ func requireFilters(ctx arg_parser.ParseContext, command string) {
 	}
 }
 
+// Returns the value of a flag (e.g., `--addr`) and whether the flag was used.
+// If a flag is used more than once, the last value wins.
+func getFlag(ctx arg_parser.ParseContext, name string) (string, bool) {
+	value, found := "", false
+
+	for _, arg := range ctx.Args {
+		if flag, ok := arg.(arg_parser.FlagArg); ok && flag.Name == name {
+			value, found = flag.Value, true
+		}
+	}
+
+	return value, found
+}
+
+// Asks the user to confirm a change, unless the `-y` flag was used.
+func confirm(ctx arg_parser.ParseContext) bool {
+	if _, ok := getFlag(ctx, "yes"); ok {
+		return true
+	}
+
+	return printer.Confirm("Are you sure you want to continue?")
+}
+
+// Returns the cutoff from the `older-than:` arg (e.g., `older-than:30d`), or
+// the zero time if the arg was not used.
+func getOlderThan(ctx arg_parser.ParseContext) (time.Time, error) {
+	var cutoff time.Time
+
+	for _, arg := range ctx.Args {
+		if arg, ok := arg.(arg_parser.TextArg); ok {
+			for _, word := range strings.Fields(arg.Text) {
+				age, ok := strings.CutPrefix(word, "older-than:")
+				if !ok {
+					return cutoff, fmt.Errorf("Unexpected argument \"%s\"", word)
+				}
+
+				t, err := utils.ParseAge(age, time.Now())
+				if err != nil {
+					return cutoff, err
+				}
+
+				cutoff = t
+			}
+		}
+	}
+
+	return cutoff, nil
+}
+
+// Returns the output format requested with the `format=` config override,
+// defaulting to the standard table output.
+func getFormat(ctx arg_parser.ParseContext) printer.Format {
+	format := printer.FormatTable
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.FormatConfig); ok {
+			if f, ok := printer.FormatFromStr(config.Format); ok {
+				format = f
+			} else {
+				printer.Error(fmt.Errorf("Invalid format \"%s\"", config.Format))
+			}
+		}
+	}
+
+	return format
+}
+
+// Returns how tables should handle rows wider than the terminal, configured
+// with the `overflow=` config override.
+func getOverflow(ctx arg_parser.ParseContext) printer.Overflow {
+	overflow := printer.OverflowWrap
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.OverflowConfig); ok {
+			if o, ok := printer.OverflowFromStr(config.Overflow); ok {
+				overflow = o
+			} else {
+				printer.Error(fmt.Errorf("Invalid overflow \"%s\"", config.Overflow))
+			}
+		}
+	}
+
+	return overflow
+}
+
+// Applies the priorities, theme, and color rules from the `priorities=`,
+// `theme=`, and `color.*=` config overrides.
+func Configure(ctx arg_parser.ParseContext) {
+	var colors []arg_parser.ColorConfig
+	theme, _ := printer.ThemeFromStr("dark")
+
+	for _, config := range ctx.Config {
+		switch config := config.(type) {
+		case arg_parser.ThemeConfig:
+			if t, ok := printer.ThemeFromStr(config.Theme); ok {
+				theme = t
+			} else {
+				printer.Error(fmt.Errorf("Invalid theme \"%s\"", config.Theme))
+			}
+
+		case arg_parser.PrioritiesConfig:
+			if err := storage.SetPriorities(config.Levels); err != nil {
+				printer.Error(err)
+			}
+
+		case arg_parser.ColorConfig:
+			colors = append(colors, config)
+		}
+	}
+
+	// Color rules are applied after choosing the theme so they can customize
+	// any of the built-in themes.
+	for _, config := range colors {
+		if err := theme.SetColor(config.Key, config.Value); err != nil {
+			printer.Error(err)
+		}
+	}
+
+	printer.SetTheme(theme)
+}
+
+// Returns a filter matching a single task by its unique id.
+func taskFilter(id string) sql_builder.Filter {
+	return sql_builder.Filter{
+		Key:      "tasks.id",
+		Operator: sql_builder.Eq,
+		Value:    quote(id),
+	}
+}
+
+// Returns a filter matching tasks by their short ids.
+func shortIdsFilter(ids []int) sql_builder.Filter {
+	var values []string
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+
+	return sql_builder.Filter{
+		Key:      "tasks.id",
+		Operator: sql_builder.In,
+		Value: fmt.Sprintf(
+			"(select task_id from assignments where id in (%s))",
+			strings.Join(values, ", "),
+		),
+	}
+}
+
+// Returns a filter matching the subtasks of the task with the short id, or
+// tasks without a parent if the id is empty.
+func parentFilter(id string) (sql_builder.Filter, error) {
+	if id == "" {
+		return sql_builder.Filter{Key: "data ->> 'parent'", Operator: sql_builder.Is, Value: "null"}, nil
+	}
+
+	if _, err := strconv.Atoi(id); err != nil {
+		return sql_builder.Filter{}, fmt.Errorf("Invalid parent \"%s\", expected a task id", id)
+	}
+
+	return sql_builder.Filter{
+		Key:      "data ->> 'parent'",
+		Operator: sql_builder.Eq,
+		Value:    fmt.Sprintf("(select task_id from assignments where id = %s)", id),
+	}, nil
+}
+
+// Returns a filter matching tasks with the priority. Priorities are matched
+// without case, including priorities saved before they were validated, and
+// `priority.above:` and `priority.below:` match tasks with a higher or lower
+// priority.
+func priorityFilter(scope arg_parser.Scope, value string) (sql_builder.Filter, error) {
+	if scope == arg_parser.ScopePriority {
+		priority, err := storage.ParsePriority(value)
+		if err != nil {
+			return sql_builder.Filter{}, err
+		}
+
+		return sql_builder.Filter{
+			Key:      "data ->> 'priority' collate nocase",
+			Operator: sql_builder.Eq,
+			Value:    quote(priority),
+		}, nil
+	}
+
+	priorities, err := storage.PrioritiesBeyond(value, scope == arg_parser.ScopePriorityAbove)
+	if err != nil {
+		return sql_builder.Filter{}, err
+	}
+
+	var values []string
+	for _, priority := range priorities {
+		values = append(values, quote(priority))
+	}
+
+	return sql_builder.Filter{
+		Key:      "data ->> 'priority' collate nocase",
+		Operator: sql_builder.In,
+		Value:    fmt.Sprintf("(%s)", strings.Join(values, ", ")),
+	}, nil
+}
+
+// Quotes a string for use in SQL.
+func quote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", "''") + "'"
+}
+
+// Returns the SQL expression for a field of a task. Fields that aren't one of
+// the task's own text fields are read from its attributes.
+func fieldKey(field string) string {
+	switch field {
+	case "title", "priority", "status", "project":
+		return fmt.Sprintf("data ->> '%s'", field)
+	default:
+		return fmt.Sprintf("data -> 'attributes' ->> %s", quote(field))
+	}
+}
+
+// Returns true if the tag is a glob pattern, such as `work.*` or `*review*`.
+func isTagPattern(tag string) bool {
+	return strings.ContainsAny(tag, "*?[")
+}
+
+// Returns true if tag filters should also match child tags, configured with
+// the `tag.children=` config override.
+func matchChildTags(ctx arg_parser.ParseContext) bool {
+	children := false
+
+	for _, config := range ctx.Config {
+		if config, ok := config.(arg_parser.TagConfig); ok {
+			children = config.Children
+		}
+	}
+
+	return children
+}
+
+// Returns a filter matching tasks that include or exclude the tag. Tags with
+// wildcards are matched as glob patterns, otherwise tags are matched exactly.
+// If children is true, tags also match their child tags (e.g., `work` matches
+// `work.infra`).
+func tagFilter(tag string, operator arg_parser.Operator, children bool) sql_builder.Filter {
+	condition := "tags.value = " + quote(tag)
+
+	if isTagPattern(tag) {
+		condition = "tags.value glob " + quote(tag)
+	} else if children {
+		condition = fmt.Sprintf("(%s or tags.value glob %s)", condition, quote(tag+".*"))
+	}
+
+	op := sql_builder.Exists
+	if operator == arg_parser.Exclude {
+		op = sql_builder.NotExists
+	}
+
+	return sql_builder.Filter{
+		Operator: op,
+		Value:    fmt.Sprintf("(select 1 from json_each(tasks.data, '$.tags') as tags where %s)", condition),
+	}
+}
+
+// Returns a filter matching tasks with a date field on the given day.
+func dateFilter(field string, value string) (sql_builder.Filter, error) {
+	start, err := utils.ParseDate(value, time.Now())
+	if err != nil {
+		return sql_builder.Filter{}, err
+	}
+
+	end := start.AddDate(0, 0, 1)
+
+	return sql_builder.Filter{
+		Key:      fmt.Sprintf("CAST(strftime('%%s', data ->> '%s') AS INTEGER)", field),
+		Operator: sql_builder.Between,
+		Value:    fmt.Sprintf("%d AND %d", start.Unix(), end.Unix()-1),
+	}, nil
+}
+
+// Builds the filters for the command, exiting if any filter is invalid.
 func buildFilters(ctx arg_parser.ParseContext) []sql_builder.Filter {
+	filters, err := parseFilters(ctx)
+	if err != nil {
+		printer.Error(err)
+	}
+
+	return filters
+}
+
+func parseFilters(ctx arg_parser.ParseContext) ([]sql_builder.Filter, error) {
 	var filters []sql_builder.Filter
 
 	for _, f := range ctx.Filters {
 		switch filter := f.(type) {
 		case arg_parser.IdFilter:
-			var ids []string
-			for _, id := range filter.Ids {
-				ids = append(ids, strconv.Itoa(id))
-			}
-
-			filters = append(filters, sql_builder.Filter{
-				Key:      "tasks.id",
-				Operator: sql_builder.In,
-				Value: fmt.Sprintf(
-					"(select task_id from assignments where id in (%s))",
-					strings.Join(ids, ", "),
-				),
-			})
+			filters = append(filters, shortIdsFilter(filter.Ids))
 
 		case arg_parser.TextFilter:
 			filters = append(filters, sql_builder.Filter{
 				Key:      "data ->> 'title'",
 				Operator: sql_builder.Like,
-				Value:    fmt.Sprintf("'%%%s%%'", filter.Text),
+				Value:    quote("%" + filter.Text + "%"),
 			})
 
 		case arg_parser.TagFilter:
-			operator := sql_builder.Like
-			if filter.Operator == arg_parser.Exclude {
-				operator = sql_builder.NotLike
+			filters = append(filters, tagFilter(filter.Tag, filter.Operator, matchChildTags(ctx)))
+
+		case arg_parser.RegexFilter:
+			// Patterns are validated before querying so invalid patterns aren't
+			// reported as a failure to list tasks.
+			if _, err := regexp.Compile(filter.Pattern); err != nil {
+				return nil, fmt.Errorf("Invalid regular expression \"%s\": %w", filter.Pattern, err)
 			}
 
 			filters = append(filters, sql_builder.Filter{
-				Key:      "JSON_EXTRACT(data, '$.tags')",
-				Operator: operator,
-				Value:    fmt.Sprintf("'%%\"%s\"%%'", filter.Tag),
+				Key:      fieldKey(filter.Field),
+				Operator: sql_builder.Regexp,
+				Value:    quote(filter.Pattern),
 			})
 
 		case arg_parser.ScopedFilter:
+			switch filter.Scope {
+			case arg_parser.ScopeDue, arg_parser.ScopeScheduled, arg_parser.ScopeWait:
+				f, err := dateFilter(string(filter.Scope), filter.Value)
+				if err != nil {
+					return nil, err
+				}
+
+				filters = append(filters, f)
+				continue
+
+			case arg_parser.ScopeFrom:
+				return nil, errors.New("Blueprints can only be used when adding tasks")
+
+			case arg_parser.ScopePriority, arg_parser.ScopePriorityAbove, arg_parser.ScopePriorityBelow:
+				f, err := priorityFilter(filter.Scope, filter.Value)
+				if err != nil {
+					return nil, err
+				}
+
+				filters = append(filters, f)
+				continue
+
+			case arg_parser.ScopeParent:
+				f, err := parentFilter(filter.Value)
+				if err != nil {
+					return nil, err
+				}
+
+				filters = append(filters, f)
+				continue
+			}
+
 			filters = append(filters, sql_builder.Filter{
 				Key:      fmt.Sprintf("data ->> '%s'", filter.Scope),
 				Operator: sql_builder.Eq,
-				Value:    fmt.Sprintf("'%s'", filter.Value),
+				Value:    quote(filter.Value),
 			})
 		}
 	}
 
-	return filters
+	return filters, nil
 }
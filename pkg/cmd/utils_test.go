@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/storage"
+	"github.com/mskelton/tsk/internal/test_utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// Adds tasks with the titles, returning their short ids.
+func addTasks(t *testing.T, titles ...string) []int64 {
+	var ids []int64
+
+	for _, title := range titles {
+		task := storage.NewTask()
+		task.Title = title
+
+		id, err := storage.Add(task)
+		assert.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// Returns the titles of the tasks matching the filters.
+func filterTitles(t *testing.T, filters string) []string {
+	parser := arg_parser.New()
+	ctx := parser.Parse(append(strings.Fields(filters), "list"))
+
+	f, err := parseFilters(ctx)
+	assert.NoError(t, err)
+
+	tasks, err := storage.GetTasks(f)
+	assert.NoError(t, err)
+
+	titles := []string{}
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+
+	return titles
+}
+
+func TestFiltersQuoteValues(t *testing.T) {
+	test_utils.UseTempDB(t)
+	addTasks(t, "Fix it's broken", "Buy milk")
+
+	assert.Equal(t, []string{"Fix it's broken"}, filterTitles(t, "it's"))
+	assert.Empty(t, filterTitles(t, "x%' or 1=1 or '%"))
+	assert.Empty(t, filterTitles(t, "project:x' or 1=1 or 'x"))
+}
+
+func TestTagFilters(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	tags := map[string][]string{
+		"Deploy":     {"work"},
+		"Infra":      {"work.infra"},
+		"Underscore": {"a_b"},
+		"Lookalike":  {"axb"},
+		"Percent":    {"50%"},
+		"Review":     {"code-review", "home"},
+		"Untagged":   {},
+	}
+
+	for _, title := range []string{"Deploy", "Infra", "Underscore", "Lookalike", "Percent", "Review", "Untagged"} {
+		task := storage.NewTask()
+		task.Title = title
+		task.Tags = tags[title]
+
+		_, err := storage.Add(task)
+		assert.NoError(t, err)
+	}
+
+	// Tags are matched exactly rather than with `like`
+	assert.Equal(t, []string{"Underscore"}, filterTitles(t, "+a_b"))
+	assert.Equal(t, []string{"Percent"}, filterTitles(t, "+50%"))
+	assert.Empty(t, filterTitles(t, "+5%"))
+	assert.Empty(t, filterTitles(t, "+wor"))
+
+	// Child tags are only matched with `tag.children`
+	assert.Equal(t, []string{"Deploy"}, filterTitles(t, "+work"))
+	assert.Equal(t, []string{"Deploy", "Infra"}, filterTitles(t, "tag.children=yes +work"))
+	assert.Equal(t, []string{"Underscore", "Lookalike", "Percent", "Review", "Untagged"}, filterTitles(t, "tag.children=yes -work"))
+	assert.Equal(t, []string{"Infra", "Underscore", "Lookalike", "Percent", "Review", "Untagged"}, filterTitles(t, "-work"))
+
+	// Tags with wildcards are matched as glob patterns
+	assert.Equal(t, []string{"Deploy", "Underscore", "Lookalike", "Percent", "Review", "Untagged"}, filterTitles(t, "-work.*"))
+	assert.Equal(t, []string{"Review"}, filterTitles(t, "+*review*"))
+	assert.Equal(t, []string{"Underscore", "Lookalike"}, filterTitles(t, "+a?b"))
+	assert.Equal(t, []string{"Review"}, filterTitles(t, "+*review* +home -work*"))
+}
+
+func TestPriorityFilters(t *testing.T) {
+	test_utils.UseTempDB(t)
+
+	// Priorities saved before they were validated may be lowercase
+	for _, priority := range []string{"H", "m", "L", ""} {
+		task := storage.NewTask()
+		task.Title = "Priority " + priority
+		task.Priority = priority
+
+		_, err := storage.Add(task)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"Priority H"}, filterTitles(t, "priority:h"))
+	assert.Equal(t, []string{"Priority m"}, filterTitles(t, "priority:M"))
+	assert.Equal(t, []string{"Priority H", "Priority m"}, filterTitles(t, "priority.above:L"))
+	assert.Equal(t, []string{"Priority m", "Priority L"}, filterTitles(t, "priority.below:h"))
+	assert.Equal(t, []string{"Priority "}, filterTitles(t, "priority:"))
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mskelton/tsk/internal/arg_parser"
+	"github.com/mskelton/tsk/internal/printer"
+	"github.com/mskelton/tsk/internal/storage"
+)
+
+// Lists the tasks that are hidden from the task list until their wait date,
+// soonest first.
+func Waiting(ctx arg_parser.ParseContext) {
+	format := getFormat(ctx)
+	tasks, err := storage.ListWaiting(buildFilters(ctx))
+	if err != nil {
+		printer.Error(err)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Wait.Before(*tasks[j].Wait)
+	})
+
+	if format.IsData() {
+		printer.JSON(format, tasks)
+		return
+	}
+
+	if len(tasks) == 0 && format == printer.FormatTable {
+		printer.Message("No waiting tasks match filters")
+		return
+	}
+
+	table := printer.Table{
+		Columns:  []string{"ID", "Wait", "P", "Project", "Tags", "Title"},
+		Rows:     []printer.Row{},
+		Overflow: getOverflow(ctx),
+	}
+
+	for _, task := range tasks {
+		table.Rows = append(table.Rows, printer.Row{
+			Cells: []string{
+				strconv.Itoa(task.ShortId),
+				formatDate(task.Wait),
+				task.Priority,
+				task.Project,
+				strings.Join(task.Tags, " "),
+				task.Title,
+			},
+			Style: printer.RuleStyle(task.Priority, task.Tags),
+		})
+	}
+
+	table.PrintAs(format)
+}
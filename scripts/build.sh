@@ -25,7 +25,7 @@ for platform in "${platforms[@]}"; do
 	fi
 
 	# Build the binary
-	env GOOS="$GOOS" GOARCH="$GOARCH" go build -o $bin
+	env GOOS="$GOOS" GOARCH="$GOARCH" go build -tags sqlite_fts5 -o $bin
 
 	# Zip the binary
 	mkdir -p dist